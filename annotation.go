@@ -0,0 +1,211 @@
+package chart
+
+import (
+	"math"
+)
+
+// AnnotationKind distinguishes the different types of annotations.
+type AnnotationKind int
+
+const (
+	TextAnnotation  AnnotationKind = iota // free text at (X,Y)
+	ArrowAnnotation                       // arrow from text at (X2,Y2) pointing to (X,Y)
+	HLineAnnotation                       // horizontal reference line at Y
+	VLineAnnotation                       // vertical reference line at X
+	XSpanAnnotation                       // shaded span from X to X2 over the full height
+	YSpanAnnotation                       // shaded span from Y to Y2 over the full width
+)
+
+// AnnotationLayer determines when an annotation is drawn relative to the
+// grid and the data of a chart.
+type AnnotationLayer int
+
+const (
+	DefaultLayer    AnnotationLayer = iota // lines and spans in BelowDataLayer, text and arrows in AboveDataLayer
+	BackgroundLayer                        // below grid and axis
+	BelowDataLayer                         // above grid and axis, below data
+	AboveDataLayer                         // on top of data
+)
+
+// Annotation is a text, arrow, reference line or shaded span drawn on an
+// axis based chart. Positions are in data coordinates unless Screen is true
+// in which case X, Y, X2 and Y2 are screen coordinates. Positions on
+// date/time axis are seconds since the Unix epoch.
+type Annotation struct {
+	Kind   AnnotationKind
+	X, Y   float64         // position of text, head of arrow, position of line, start of span
+	X2, Y2 float64         // position of arrow tail (and its text), end of span
+	Text   string          // text to display, label of arrow, line or span
+	Align  string          // alignment of TextAnnotation as in Graphics.Text, "" is "cc"
+	Screen bool            // X, Y, X2 and Y2 are in screen coordinates
+	Layer  AnnotationLayer // when to draw the annotation
+	Style  Style           // style of annotation, empty style uses Options of chart
+}
+
+// Annotations is a list of annotations on an axis based chart.
+type Annotations []Annotation
+
+// AddText adds text at (x,y).
+func (a *Annotations) AddText(x, y float64, text string, style Style) {
+	*a = append(*a, Annotation{Kind: TextAnnotation, X: x, Y: y, Text: text, Style: style})
+}
+
+// AddArrow adds an arrow pointing to (x,y) starting at (tx,ty) where text is
+// printed.
+func (a *Annotations) AddArrow(x, y, tx, ty float64, text string, style Style) {
+	*a = append(*a, Annotation{Kind: ArrowAnnotation, X: x, Y: y, X2: tx, Y2: ty, Text: text, Style: style})
+}
+
+// AddHLine adds a horizontal reference line at y labeled with label.
+func (a *Annotations) AddHLine(y float64, label string, style Style) {
+	*a = append(*a, Annotation{Kind: HLineAnnotation, Y: y, Text: label, Style: style})
+}
+
+// AddVLine adds a vertical reference line at x labeled with label.
+func (a *Annotations) AddVLine(x float64, label string, style Style) {
+	*a = append(*a, Annotation{Kind: VLineAnnotation, X: x, Text: label, Style: style})
+}
+
+// AddXSpan shades the x range [x0,x1] and labels it with label.
+func (a *Annotations) AddXSpan(x0, x1 float64, label string, style Style) {
+	*a = append(*a, Annotation{Kind: XSpanAnnotation, X: x0, X2: x1, Text: label, Style: style})
+}
+
+// AddYSpan shades the y range [y0,y1] and labels it with label.
+func (a *Annotations) AddYSpan(y0, y1 float64, label string, style Style) {
+	*a = append(*a, Annotation{Kind: YSpanAnnotation, Y: y0, Y2: y1, Text: label, Style: style})
+}
+
+// layer returns the effective layer of annotation a.
+func (a Annotation) layer() AnnotationLayer {
+	if a.Layer != DefaultLayer {
+		return a.Layer
+	}
+	switch a.Kind {
+	case TextAnnotation, ArrowAnnotation:
+		return AboveDataLayer
+	}
+	return BelowDataLayer
+}
+
+// drawAnnotations draws all annotations in layer on g. The ranges xr and yr
+// must be set up already.
+func drawAnnotations(g Graphics, annotations Annotations, layer AnnotationLayer, xr, yr Range, options PlotOptions) {
+	if len(annotations) == 0 {
+		return
+	}
+	xa, xe := xr.Data2Screen(xr.Min), xr.Data2Screen(xr.Max)
+	ya, ye := yr.Data2Screen(yr.Min), yr.Data2Screen(yr.Max)
+	inx := func(x int) bool { return x >= imin(xa, xe) && x <= imax(xa, xe) }
+	iny := func(y int) bool { return y >= imin(ya, ye) && y <= imax(ya, ye) }
+
+	for _, a := range annotations {
+		if a.layer() != layer {
+			continue
+		}
+		style := a.Style
+		if style.empty() {
+			switch a.Kind {
+			case HLineAnnotation, VLineAnnotation:
+				style = elementStyle(options, ReferenceLineElement)
+			case XSpanAnnotation, YSpanAnnotation:
+				style = elementStyle(options, SpanElement)
+			default:
+				style = elementStyle(options, AnnotationElement)
+			}
+		}
+		if style.LineColor == nil {
+			style.LineColor = style.FillColor
+		}
+		fw, fh, _ := g.FontMetrics(style.Font)
+		sep := imax(1, int(fw+0.5))
+
+		// screen coordinates
+		sx, sy, sx2, sy2 := int(a.X), int(a.Y), int(a.X2), int(a.Y2)
+		if !a.Screen {
+			sx, sy = xr.Data2Screen(a.X), yr.Data2Screen(a.Y)
+			sx2, sy2 = xr.Data2Screen(a.X2), yr.Data2Screen(a.Y2)
+		}
+
+		switch a.Kind {
+		case TextAnnotation:
+			align := a.Align
+			if align == "" {
+				align = "cc"
+			}
+			g.Text(sx, sy, a.Text, align, 0, style.Font)
+
+		case ArrowAnnotation:
+			g.Line(sx2, sy2, sx, sy, style)
+			drawArrowHead(g, sx2, sy2, sx, sy, imax(2, (2*fh)/3), style)
+			if a.Text != "" {
+				align := []byte("cc")
+				switch {
+				case sy > sy2:
+					align[0] = 'b'
+				case sy < sy2:
+					align[0] = 't'
+				}
+				switch {
+				case sx > sx2:
+					align[1] = 'r'
+				case sx < sx2:
+					align[1] = 'l'
+				}
+				g.Text(sx2, sy2, a.Text, string(align), 0, style.Font)
+			}
+
+		case HLineAnnotation:
+			if !iny(sy) {
+				continue
+			}
+			g.Line(xa, sy, xe, sy, style)
+			if a.Text != "" {
+				g.Text(xe-sep, sy-imax(1, fh/4), a.Text, "br", 0, style.Font)
+			}
+
+		case VLineAnnotation:
+			if !inx(sx) {
+				continue
+			}
+			g.Line(sx, ya, sx, ye, style)
+			if a.Text != "" {
+				g.Text(sx+sep, imin(ya, ye)+fh/4, a.Text, "tl", 0, style.Font)
+			}
+
+		case XSpanAnnotation:
+			x0, x1 := clip(sx, imin(xa, xe), imax(xa, xe)), clip(sx2, imin(xa, xe), imax(xa, xe))
+			if x0 == x1 {
+				continue
+			}
+			g.Rect(imin(x0, x1), imin(ya, ye), iabs(x1-x0), iabs(ye-ya), style)
+			if a.Text != "" {
+				g.Text((x0+x1)/2, imin(ya, ye)+fh/4, a.Text, "tc", 0, style.Font)
+			}
+
+		case YSpanAnnotation:
+			y0, y1 := clip(sy, imin(ya, ye), imax(ya, ye)), clip(sy2, imin(ya, ye), imax(ya, ye))
+			if y0 == y1 {
+				continue
+			}
+			g.Rect(imin(xa, xe), imin(y0, y1), iabs(xe-xa), iabs(y1-y0), style)
+			if a.Text != "" {
+				g.Text(imin(xa, xe)+sep, (y0+y1)/2, a.Text, "cl", 0, style.Font)
+			}
+		}
+	}
+}
+
+// drawArrowHead draws the head of size s of an arrow from (x0,y0) to (x1,y1).
+func drawArrowHead(g Graphics, x0, y0, x1, y1, s int, style Style) {
+	if x0 == x1 && y0 == y1 {
+		return
+	}
+	style.LineStyle = SolidLine
+	alpha := math.Atan2(float64(y1-y0), float64(x1-x0))
+	for _, beta := range []float64{alpha + 2.6, alpha - 2.6} {
+		x := x1 + int(float64(s)*math.Cos(beta)+0.5)
+		y := y1 + int(float64(s)*math.Sin(beta)+0.5)
+		g.Line(x1, y1, x, y, style)
+	}
+}
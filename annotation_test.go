@@ -0,0 +1,65 @@
+package chart_test
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/vdobler/chart"
+	"github.com/vdobler/chart/txtg"
+)
+
+func TestAnnotations(t *testing.T) {
+	c := chart.ScatterChart{}
+	c.Key.Hide = true
+	c.XRange.Fixed(0, 10, 2)
+	c.YRange.Fixed(0, 10, 2)
+	c.AddDataPair("", []float64{2, 4, 6, 8, 9}, []float64{6, 6, 8, 4, 2}, chart.PlotStylePoints,
+		chart.Style{Symbol: 'o'})
+
+	line := chart.Style{Symbol: '=', LineWidth: 1}
+	span := chart.Style{Symbol: '.', FillColor: color.NRGBA{0x80, 0x80, 0x80, 0x80}}
+	c.Annotations.AddHLine(6, "ref", line)                  // below data: point stays visible
+	c.Annotations.AddVLine(6, "", line)                     // below data: point stays visible
+	c.Annotations.AddText(8, 4, "T", chart.Style{})         // above data: hides point
+	c.Annotations.AddXSpan(0.5, 1.5, "S", span)             // shades full height
+	c.Annotations.AddArrow(4, 2, 4, 0.5, "A", line)         // arrow pointing up
+	c.Annotations = append(c.Annotations, chart.Annotation{ // on top of point
+		Kind: chart.HLineAnnotation, Y: 2, Layer: chart.AboveDataLayer, Style: chart.Style{Symbol: '~'}})
+
+	g := txtg.New(60, 23)
+	c.Plot(g)
+	lines := strings.Split(g.String(), "\n")
+	at := func(x, y float64) byte {
+		return lines[c.YRange.Data2Screen(y)][c.XRange.Data2Screen(x)]
+	}
+	top := c.YRange.Data2Screen(c.YRange.Max)
+
+	for i, tc := range []struct {
+		x, y float64
+		want byte
+	}{
+		{2, 6, 'o'}, {3, 6, '='}, // hline below data
+		{6, 8, 'o'}, {6, 5, '='}, // vline below data
+		{8, 4, 'T'},              // text above data
+		{1, 5, '.'}, {1, 9, '.'}, // span
+		{4, 1, '='},              // arrow shaft
+		{9, 2, '~'}, {5, 2, '~'}, // hline above data
+	} {
+		if got := at(tc.x, tc.y); got != tc.want {
+			t.Errorf("%d: at (%g,%g) got %q, want %q\n%s", i, tc.x, tc.y, got, tc.want, g)
+		}
+	}
+
+	// Labels: hline right aligned above line, span at top, arrow text at tail.
+	right := c.XRange.Data2Screen(c.XRange.Max)
+	if got := lines[c.YRange.Data2Screen(6)-1][right-4 : right-1]; got != "ref" {
+		t.Errorf("hline label: got %q\n%s", got, g)
+	}
+	if got := lines[top][c.XRange.Data2Screen(0.5):c.XRange.Data2Screen(1.5)]; !strings.Contains(got, "S") {
+		t.Errorf("span label: got %q\n%s", got, g)
+	}
+	if got := at(4, 0.5); got != 'A' {
+		t.Errorf("arrow label: got %q\n%s", got, g)
+	}
+}
@@ -10,17 +10,26 @@ import (
 // BarChart draws simple bar charts.
 // (Use CategoricalBarChart if your x axis is categorical, that is not numeric.)
 //
-// Stacking is on a "both bars have _identical_ x values" basis.
+// Stacking is on a "both bars have _identical_ x values" basis. Positive
+// values are stacked above and negative values below zero.
+// Diverging stacks show the magnitude of the values of the data sets before
+// the Neutral one below zero and those after it above zero; the neutral data
+// set is centered on zero. Use Percent to compare stacks of different size.
 type BarChart struct {
 	XRange, YRange Range
-	Title          string      // Title of the chart
-	Key            Key         // Key/Legend
-	Horizontal     bool        // Display as horizontal bars (unimplemented)
-	Stacked        bool        // Display different data sets ontop of each other (default is side by side)
-	ShowVal        int         // Display values: 0: don't show; 1: above bar, 2: centerd in bar; 3: at top of bar
-	SameBarWidth   bool        // all data sets use the same (smalest of all data sets) bar width
-	BarWidthFac    float64     // if nonzero: scale determined bar width with this factor
-	Options        PlotOptions // visual apperance, nil to use DefaultOptions
+	Title          string               // Title of the chart
+	Key            Key                  // Key/Legend
+	Horizontal     bool                 // Display as horizontal bars (unimplemented)
+	Stacked        bool                 // Display different data sets ontop of each other (default is side by side)
+	Percent        bool                 // Stacked only: normalize each stack to 100 (percent)
+	Diverging      bool                 // Stacked only: stack data sets below and above zero (e.g. Likert scales)
+	Neutral        string               // Diverging only: name of data set centered on zero, "": half of the data sets below zero
+	ShowVal        int                  // Display values: 0: don't show; 1: above bar, 2: centerd in bar; 3: at top of bar
+	FmtVal         func(float64) string // format shown values (e.g. NumberFormat.Format), nil for default
+	SameBarWidth   bool                 // all data sets use the same (smalest of all data sets) bar width
+	BarWidthFac    float64              // if nonzero: scale determined bar width with this factor
+	Options        PlotOptions          // visual apperance, nil to use DefaultOptions
+	Annotations    Annotations          // text, arrows, reference lines and spans
 	Data           []BarChartData
 }
 
@@ -29,6 +38,7 @@ type BarChartData struct {
 	Name    string
 	Style   Style
 	Samples []Point
+	YErr    [][2]float64 // optional error interval [low,high] of each sample, NaN for no error bar
 }
 
 // AddData adds the data to the chart.
@@ -37,7 +47,7 @@ func (c *BarChart) AddData(name string, data []Point, style Style) {
 		c.XRange.init()
 		c.YRange.init()
 	}
-	c.Data = append(c.Data, BarChartData{name, style, data})
+	c.Data = append(c.Data, BarChartData{Name: name, Style: style, Samples: data})
 	for _, d := range data {
 		c.XRange.autoscale(d.X)
 		c.YRange.autoscale(d.Y)
@@ -48,6 +58,25 @@ func (c *BarChart) AddData(name string, data []Point, style Style) {
 	}
 }
 
+// AddDataGeneric adds the data to the chart and draws the (possibly
+// asymmetric) y errors of data as error bars on the bars.
+func (c *BarChart) AddDataGeneric(name string, data []XYErrValue, style Style) {
+	points := make([]Point, len(data))
+	yerr := make([][2]float64, len(data))
+	for i, d := range data {
+		points[i] = Point{X: d.XVal(), Y: d.YVal()}
+		yerr[i][0], yerr[i][1] = d.YErr()
+	}
+	c.AddData(name, points, style)
+	c.Data[len(c.Data)-1].YErr = yerr
+	for _, e := range yerr {
+		if !math.IsNaN(e[0]) && !math.IsNaN(e[1]) {
+			c.YRange.autoscale(e[0])
+			c.YRange.autoscale(e[1])
+		}
+	}
+}
+
 // AddDataPair is a convenience method to add all the (x[i],y[i]) pairs to the chart.
 func (c *BarChart) AddDataPair(name string, x, y []float64, style Style) {
 	n := imin(len(x), len(y))
@@ -64,41 +93,16 @@ func (c *BarChart) rescaleStackedY() {
 	}
 
 	// rescale y-axis
-	highSize := 0
-	if len(c.Data) > 0 {
-		highSize = len(c.Data[0].Samples)
-	}
-	lowSize := 0
-	if len(c.Data) > 0 {
-		lowSize = len(c.Data[0].Samples)
-	}
-	high := make(map[float64]float64, 2*highSize)
-	low := make(map[float64]float64, 2*lowSize)
-	min, max := c.YRange.DataMin, c.YRange.DataMax
-	for _, d := range c.Data {
-		for _, p := range d.Samples {
-			x, y := p.X, p.Y
-			if y == 0 {
-				continue
-			}
-			if y > 0 {
-				if cur, ok := high[x]; ok {
-					high[x] = cur + y
-				} else {
-					high[x] = y
-				}
-				if high[x] > max {
-					max = high[x]
-				}
-			} else {
-				if cur, ok := low[x]; ok {
-					low[x] = cur - y
-				} else {
-					low[x] = y
-				}
-				if low[x] < min {
-					min = low[x]
-				}
+	// The stacks span all values and start at 0; DataMin and DataMax may
+	// stem from an earlier Plot (e.g. with Percent) and are not used.
+	lo, hi, _ := c.stack()
+	min, max := 0.0, 0.0
+	for dn := range lo {
+		for i := range lo[dn] {
+			min = fmin(min, lo[dn][i])
+			max = fmax(max, hi[dn][i])
+			if e0, e1, ok := c.errorBar(dn, i, lo[dn][i], hi[dn][i]); ok {
+				min, max = fmin(min, fmin(e0, e1)), fmax(max, fmax(e0, e1))
 			}
 		}
 	}
@@ -116,11 +120,109 @@ func (c *BarChart) rescaleStackedY() {
 	if max <= 0 {
 		c.YRange.DataMax, c.YRange.Max = 0, 0
 		c.YRange.MaxMode.Fixed, c.YRange.MaxMode.Value = true, 0
+	} else if c.Percent && !c.Diverging && min >= 0 {
+		c.YRange.DataMax, c.YRange.Max = 100, 100
+		c.YRange.MaxMode.Fixed, c.YRange.MaxMode.Value = true, 100
 	} else {
 		c.YRange.DataMax, c.YRange.Max = max, max
 	}
 }
 
+// stack returns the lower and upper end of each bar in stacked mode and the
+// value to show on the bar.
+func (c *BarChart) stack() (lo, hi, val [][]float64) {
+	lo, hi, val = make([][]float64, len(c.Data)), make([][]float64, len(c.Data)), make([][]float64, len(c.Data))
+	total := make(map[float64]float64)
+	for dn, d := range c.Data {
+		lo[dn], hi[dn], val[dn] = make([]float64, len(d.Samples)), make([]float64, len(d.Samples)), make([]float64, len(d.Samples))
+		for _, p := range d.Samples {
+			total[p.X] += math.Abs(p.Y)
+		}
+	}
+	scale := func(x, y float64) float64 {
+		if c.Percent && total[x] > 0 {
+			return 100 * y / total[x]
+		}
+		return y
+	}
+
+	high := make(map[float64]float64)
+	low := make(map[float64]float64)
+	if !c.Diverging {
+		for dn, d := range c.Data {
+			for i, p := range d.Samples {
+				y := scale(p.X, p.Y)
+				if y >= 0 {
+					lo[dn][i], hi[dn][i] = high[p.X], high[p.X]+y
+					high[p.X] += y
+				} else {
+					lo[dn][i], hi[dn][i] = low[p.X]+y, low[p.X]
+					low[p.X] += y
+				}
+				val[dn][i] = y
+			}
+		}
+		return lo, hi, val
+	}
+
+	// Diverging: neutral set centered on zero, the sets before stacked
+	// downwards (nearest to neutral first), the sets after upwards.
+	neutral, split := -1, len(c.Data)/2
+	for dn, d := range c.Data {
+		if c.Neutral != "" && d.Name == c.Neutral {
+			neutral, split = dn, dn
+		}
+	}
+	if neutral != -1 {
+		for i, p := range c.Data[neutral].Samples {
+			y := math.Abs(scale(p.X, p.Y))
+			lo[neutral][i], hi[neutral][i], val[neutral][i] = -y/2, y/2, y
+			high[p.X] += y / 2
+			low[p.X] -= y / 2
+		}
+	}
+	for dn := split - 1; dn >= 0; dn-- {
+		for i, p := range c.Data[dn].Samples {
+			y := math.Abs(scale(p.X, p.Y))
+			lo[dn][i], hi[dn][i], val[dn][i] = low[p.X]-y, low[p.X], y
+			low[p.X] -= y
+		}
+	}
+	first := split + 1
+	if neutral == -1 {
+		first = split
+	}
+	for dn := first; dn < len(c.Data); dn++ {
+		for i, p := range c.Data[dn].Samples {
+			y := math.Abs(scale(p.X, p.Y))
+			lo[dn][i], hi[dn][i], val[dn][i] = high[p.X], high[p.X]+y, y
+			high[p.X] += y
+		}
+	}
+	return lo, hi, val
+}
+
+// errorBar returns the ends of the error bar of sample i of data set dn
+// drawn from lo to hi. The error interval is moved to the end of the bar
+// and scaled like the bar.
+func (c *BarChart) errorBar(dn, i int, lo, hi float64) (e0, e1 float64, ok bool) {
+	d := c.Data[dn]
+	if i >= len(d.YErr) {
+		return 0, 0, false
+	}
+	y := d.Samples[i].Y
+	yl, yh := d.YErr[i][0], d.YErr[i][1]
+	if math.IsNaN(yl) || math.IsNaN(yh) || y == 0 {
+		return 0, 0, false
+	}
+	end, k := hi, (hi-lo)/y
+	if hi <= 0 {
+		// Bar extends downwards.
+		end, k = lo, (lo-hi)/y
+	}
+	return end + k*(yl-y), end + k*(yh-y), true
+}
+
 // Reset chart to state before plotting.
 func (c *BarChart) Reset() {
 	c.XRange.Reset()
@@ -129,11 +231,12 @@ func (c *BarChart) Reset() {
 
 // Plot renders the chart to the graphics output g.
 func (c *BarChart) Plot(g Graphics) {
+	// The range modes of the user are restored after setting up the axis.
+	minMode, maxMode := c.YRange.MinMode, c.YRange.MaxMode
+	c.rescaleStackedY()
+
 	// layout
-	layout := layout(g, c.Title, c.XRange.Label, c.YRange.Label,
-		c.XRange.TicSetting.Hide || c.XRange.TicSetting.HideLabels,
-		c.YRange.TicSetting.Hide || c.YRange.TicSetting.HideLabels,
-		&c.Key)
+	layout := layout(g, c.Title, &c.XRange, &c.YRange, &c.Key, c.Options)
 	width, height := layout.Width, layout.Height
 	topm, leftm := layout.Top, layout.Left
 	numxtics, numytics := layout.NumXtics, layout.NumYtics
@@ -145,9 +248,9 @@ func (c *BarChart) Plot(g Graphics) {
 	width -= int(2 * fw)
 	height -= fh
 
-	c.rescaleStackedY()
 	c.XRange.Setup(numxtics, numxtics+3, width, leftm, false)
 	c.YRange.Setup(numytics, numytics+2, height, topm, true)
+	c.YRange.MinMode, c.YRange.MaxMode = minMode, maxMode
 
 	// Start of drawing
 	g.Begin()
@@ -155,8 +258,10 @@ func (c *BarChart) Plot(g Graphics) {
 		drawTitle(g, c.Title, elementStyle(c.Options, TitleElement))
 	}
 
+	drawAnnotations(g, c.Annotations, BackgroundLayer, c.XRange, c.YRange, c.Options)
 	g.XAxis(c.XRange, topm+height+fh, topm, c.Options)
 	g.YAxis(c.YRange, leftm-int(2*fw), leftm+width, c.Options)
+	drawAnnotations(g, c.Annotations, BelowDataLayer, c.XRange, c.YRange, c.Options)
 
 	xf := c.XRange.Data2Screen
 	yf := c.YRange.Data2Screen
@@ -175,10 +280,9 @@ func (c *BarChart) Plot(g Graphics) {
 	// TODO: gap between bars.
 	var sbw, fbw int // ScreenBarWidth
 
-	var low, high map[float64]float64
+	var lo, hi, val [][]float64
 	if c.Stacked {
-		high = make(map[float64]float64, 50)
-		low = make(map[float64]float64, 50)
+		lo, hi, val = c.stack()
 	}
 	for dn, data := range c.Data {
 		mindeltax := c.minimumSampleSep(dn)
@@ -196,14 +300,7 @@ func (c *BarChart) Plot(g Graphics) {
 		// DebugLogger.Printf("sbw = %d ,  fbw = %d\n", sbw, fbw)
 
 		bars := make([]Barinfo, 0, len(data.Samples))
-		if c.Stacked {
-			for _, p := range data.Samples {
-				if _, ok := high[p.X]; !ok {
-					high[p.X], low[p.X] = 0, 0
-				}
-			}
-		}
-		for _, p := range data.Samples {
+		for i, p := range data.Samples {
 			x, y := p.X, p.Y
 			if y == 0 {
 				continue
@@ -215,19 +312,13 @@ func (c *BarChart) Plot(g Graphics) {
 			}
 
 			var sy, sh int
+			down := y < 0
+			blo, bhi := math.Min(0, y), math.Max(0, y)
 			if c.Stacked {
-				if y > 0 {
-					top := y + high[x]
-					sy = yf(top)
-					sh = yf(high[x]) - sy
-					high[x] = top
-
-				} else {
-					bot := low[x] + y
-					sy = yf(low[x])
-					sh = yf(bot) - sy
-					low[x] = bot
-				}
+				blo, bhi = lo[dn][i], hi[dn][i]
+				sy = yf(hi[dn][i])
+				sh = yf(lo[dn][i]) - sy
+				y, down = val[dn][i], hi[dn][i] <= 0
 			} else {
 				if y > 0 {
 					sy = yf(y)
@@ -238,7 +329,10 @@ func (c *BarChart) Plot(g Graphics) {
 				}
 			}
 			bar := Barinfo{x: sx, y: sy, w: sbw, h: sh}
-			c.addLabel(&bar, y)
+			if e0, e1, ok := c.errorBar(dn, i, blo, bhi); ok {
+				bar.err, bar.e0, bar.e1 = true, yf(e0), yf(e1)
+			}
+			c.addLabel(&bar, y, down)
 			bars = append(bars, bar)
 
 		}
@@ -246,6 +340,8 @@ func (c *BarChart) Plot(g Graphics) {
 
 	}
 
+	drawAnnotations(g, c.Annotations, AboveDataLayer, c.XRange, c.YRange, c.Options)
+
 	if !c.Key.Hide {
 		g.Key(layout.KeyX, layout.KeyY, c.Key, c.Options)
 	}
@@ -333,13 +429,27 @@ func (c *BarChart) minimumSampleSep(d int) (min float64) {
 	return
 }
 
-func (c *BarChart) addLabel(bar *Barinfo, y float64) {
-	if c.ShowVal == 0 {
+// addLabel labels bar with value y. Down bars extend downwards from zero.
+func (c *BarChart) addLabel(bar *Barinfo, y float64, down bool) {
+	fmtVal := c.FmtVal
+	if fmtVal == nil && c.Stacked && c.Percent {
+		fmtVal = func(v float64) string { return fmt.Sprintf("%.0f%%", v) }
+	}
+	barLabel(bar, y, down, c.ShowVal, fmtVal)
+}
+
+// barLabel sets the label of bar to the value y formated by fmtVal (or
+// a default format if nil) and positioned according to showVal (see
+// BarChart.ShowVal) and down which indicates bars extending downwards.
+func barLabel(bar *Barinfo, y float64, down bool, showVal int, fmtVal func(float64) string) {
+	if showVal == 0 {
 		return
 	}
 
 	var sval string
-	if math.Abs(y) >= 100 {
+	if fmtVal != nil {
+		sval = fmtVal(y)
+	} else if math.Abs(y) >= 100 {
 		sval = fmt.Sprintf("%d", int(y+0.5))
 	} else if math.Abs(y) >= 10 {
 		sval = fmt.Sprintf("%.1f", y)
@@ -350,15 +460,15 @@ func (c *BarChart) addLabel(bar *Barinfo, y float64) {
 	}
 
 	var tp string
-	switch c.ShowVal {
+	switch showVal {
 	case 1:
-		if y >= 0 {
+		if !down {
 			tp = "ot"
 		} else {
 			tp = "ob"
 		}
 	case 2:
-		if y >= 0 {
+		if !down {
 			tp = "it"
 		} else {
 			tp = "ib"
@@ -0,0 +1,54 @@
+package chart
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBarStack(t *testing.T) {
+	x := []float64{0, 1}
+	samples := []struct {
+		chart    BarChart
+		expected string
+	}{
+		{BarChart{}, "0:2 -2:0 | 2:5 -3:-2 | 5:10 0:3 | "},
+		{BarChart{Percent: true}, "0:20 -33.3:0 | 20:50 -50:-33.3 | 50:100 0:50 | "},
+		{BarChart{Diverging: true}, "-2:0 -2:0 | 0:3 0:1 | 3:8 1:4 | "},
+		{BarChart{Diverging: true, Neutral: "B"}, "-3.5:-1.5 -2.5:-0.5 | -1.5:1.5 -0.5:0.5 | 1.5:6.5 0.5:3.5 | "},
+	}
+	for i, s := range samples {
+		c := s.chart
+		c.Stacked = true
+		c.AddDataPair("A", x, []float64{2, -2}, Style{})
+		c.AddDataPair("B", x, []float64{3, -1}, Style{})
+		c.AddDataPair("C", x, []float64{5, 3}, Style{})
+		lo, hi, _ := c.stack()
+		got := ""
+		for dn := range lo {
+			for j := range lo[dn] {
+				got += fmt.Sprintf("%.3g:%.3g ", lo[dn][j], hi[dn][j])
+			}
+			got += "| "
+		}
+		if got != s.expected {
+			t.Errorf("%d: Got %q, expected %q", i, got, s.expected)
+		}
+	}
+}
+
+func TestBarErrorBar(t *testing.T) {
+	c := BarChart{Stacked: true}
+	c.AddDataGeneric("A", []XYErrValue{EPoint{X: 0, Y: 2, DeltaY: 2}, EPoint{X: 1, Y: -2, DeltaY: 2, OffY: 0.5}}, Style{})
+	c.AddDataGeneric("B", []XYErrValue{EPoint{X: 0, Y: 3, DeltaY: 1}, Point{X: 1, Y: -1}}, Style{})
+	lo, hi, _ := c.stack()
+	got := ""
+	for dn := range lo {
+		for i := range lo[dn] {
+			e0, e1, ok := c.errorBar(dn, i, lo[dn][i], hi[dn][i])
+			got += fmt.Sprintf("%g:%g:%t ", e0, e1, ok)
+		}
+	}
+	if expected := "1:3:true -2.5:-0.5:true 4.5:5.5:true 0:0:false "; got != expected {
+		t.Errorf("Got %q, expected %q", got, expected)
+	}
+}
@@ -13,14 +13,35 @@ import (
 // To faciliate standard use of box plots, the method AddSet() exists which will
 // calculate the various elents of a box (e.g. med, q3, outliers, ...) from raw
 // data.
+//
+// For horizontal box plots the YRange (the values) is drawn as the
+// horizontal axis and the XRange (the positions of the boxes) as the
+// vertical axis.
 type BoxChart struct {
 	XRange, YRange Range  // x and y axis
 	Title          string // Title of the chart
 	Key            Key    // Key/legend
 	Options        PlotOptions
 	Data           []BoxChartData // the data sets to draw
+	Annotations    Annotations    // text, arrows, reference lines and spans
+
+	Whiskers   WhiskerRule // how AddSet determines the ends of the whiskers
+	WhiskerK   float64     // factor k of IQRWhiskers, 0 is 1.5
+	WhiskerP   float64     // lower percentile of PercentileWhiskers (upper is 100-WhiskerP), 0 is 5
+	Notched    bool        // draw notches showing the 95% confidence interval of the median
+	VarWidth   bool        // box width proportional to the square root of the number of samples
+	Horizontal bool        // draw horizontal boxes
 }
 
+// WhiskerRule determines the extent of the whiskers of a box.
+type WhiskerRule int
+
+const (
+	IQRWhiskers        WhiskerRule = iota // last sample within k*IQR from the box (Tukey)
+	MinMaxWhiskers                        // minimum and maximum, no outliers
+	PercentileWhiskers                    // p and 100-p percentile, e.g. 5 and 95
+)
+
 // BoxChartData encapsulates a data set in a box chart
 type BoxChartData struct {
 	Name    string
@@ -45,12 +66,94 @@ func (c *BoxChart) NextDataSet(name string, style Style) {
 }
 
 // AddSet will add to last data set in the chart one new box calculated from data.
-// If outlier is true, than outliers (1.5*IQR from 25/75 percentil) are
-// drawn. If outlier is false, than the wiskers extend from min to max.
+// If outlier is true, than the whiskers are determined by c.Whiskers (default
+// 1.5*IQR from 25/75 percentil) and samples beyond the whiskers are drawn as
+// outliers. If outlier is false, than the wiskers extend from min to max.
 func (c *BoxChart) AddSet(x float64, data []float64, outlier bool) {
 	min, lq, med, avg, uq, max := SixvalFloat64(data, 25)
-	b := Box{X: x, Avg: avg, Med: med, Q1: lq, Q3: uq, Low: min, High: max}
+	b := Box{X: x, Avg: avg, Med: med, Q1: lq, Q3: uq, Low: min, High: max, N: len(data)}
+	if len(data) > 0 {
+		notch := 1.57 * (uq - lq) / math.Sqrt(float64(len(data)))
+		b.NotchLow, b.NotchHigh = med-notch, med+notch
+	}
+	ymin, ymax := min, max // the range includes the outliers
+
+	if outlier && c.Whiskers != MinMaxWhiskers {
+		var lower, upper float64
+		if c.Whiskers == PercentileWhiskers {
+			p := c.WhiskerP
+			if p <= 0 {
+				p = 5
+			}
+			// SixvalFloat64 sorted data.
+			lower, upper = percentilFloat64(data, p), percentilFloat64(data, 100-p)
+		} else {
+			k := c.WhiskerK
+			if k <= 0 {
+				k = 1.5
+			}
+			iqr := uq - lq
+			lower, upper = lq-k*iqr, uq+k*iqr
+		}
+		outliers := make([]float64, 0)
+		min, max = max, min
+		for _, d := range data {
+			if d > upper || d < lower {
+				outliers = append(outliers, d)
+			}
+			if d > max && d <= upper {
+				max = d
+			}
+			if d < min && d >= lower {
+				min = d
+			}
+		}
+		b.Low, b.High, b.Outliers = min, max, outliers
+	}
+	c.addBox(b, ymin, ymax)
+}
 
+// AddDigest will add to last data set in the chart one new box at x
+// calculated from the digest d. The whiskers are determined by c.Whiskers
+// but clipped to the minimum and maximum of d; outliers are not drawn as
+// the samples are not available.
+func (c *BoxChart) AddDigest(x float64, d *TDigest) {
+	if d.Count() == 0 {
+		return
+	}
+	lq, med, uq := d.Quantile(0.25), d.Quantile(0.5), d.Quantile(0.75)
+	mean := 0.0
+	means, weights := d.Centroids()
+	for i, m := range means {
+		mean += m * weights[i]
+	}
+	n := d.Count()
+	b := Box{X: x, Avg: mean / n, Med: med, Q1: lq, Q3: uq, Low: d.Min(), High: d.Max(), N: int(n + 0.5)}
+	notch := 1.57 * (uq - lq) / math.Sqrt(n)
+	b.NotchLow, b.NotchHigh = med-notch, med+notch
+
+	switch c.Whiskers {
+	case PercentileWhiskers:
+		p := c.WhiskerP
+		if p <= 0 {
+			p = 5
+		}
+		b.Low, b.High = d.Quantile(p/100), d.Quantile(1-p/100)
+	case IQRWhiskers:
+		k := c.WhiskerK
+		if k <= 0 {
+			k = 1.5
+		}
+		b.Low = math.Max(b.Low, lq-k*(uq-lq))
+		b.High = math.Min(b.High, uq+k*(uq-lq))
+	}
+	c.addBox(b, b.Low, b.High)
+}
+
+// addBox appends b to the last data set and extends the ranges by b.X and
+// min and max.
+func (c *BoxChart) addBox(b Box, min, max float64) {
+	x := b.X
 	if len(c.Data) == 0 {
 		c.Data = make([]BoxChartData, 1)
 		st := Style{LineColor: color.NRGBA{0, 0, 0, 0xff}, LineWidth: 1, LineStyle: SolidLine}
@@ -74,23 +177,6 @@ func (c *BoxChart) AddSet(x float64, data []float64, outlier bool) {
 		}
 	}
 
-	if outlier {
-		outliers := make([]float64, 0)
-		iqr := uq - lq
-		min, max = max, min
-		for _, d := range data {
-			if d > uq+1.5*iqr || d < lq-1.5*iqr {
-				outliers = append(outliers, d)
-			}
-			if d > max && d <= uq+1.5*iqr {
-				max = d
-			}
-			if d < min && d >= lq-1.5*iqr {
-				min = d
-			}
-		}
-		b.Low, b.High, b.Outliers = min, max, outliers
-	}
 	j := len(c.Data) - 1
 	c.Data[j].Samples = append(c.Data[j].Samples, b)
 }
@@ -103,11 +189,14 @@ func (c *BoxChart) Reset() {
 
 // Plot renders the chart to the graphic output g.
 func (c *BoxChart) Plot(g Graphics) {
+	// The horizontal and the vertical axis.
+	hr, vr := &c.XRange, &c.YRange
+	if c.Horizontal {
+		hr, vr = vr, hr
+	}
+
 	// layout
-	layout := layout(g, c.Title, c.XRange.Label, c.YRange.Label,
-		c.XRange.TicSetting.Hide || c.XRange.TicSetting.HideLabels,
-		c.YRange.TicSetting.Hide || c.YRange.TicSetting.HideLabels,
-		&c.Key)
+	layout := layout(g, c.Title, hr, vr, &c.Key, c.Options)
 	width, height := layout.Width, layout.Height
 	topm, leftm := layout.Top, layout.Left
 	numxtics, numytics := layout.NumXtics, layout.NumYtics
@@ -115,26 +204,39 @@ func (c *BoxChart) Plot(g Graphics) {
 
 	g.Begin()
 
-	c.XRange.Setup(numxtics, numxtics+2, width, leftm, false)
-	c.YRange.Setup(numytics, numytics+1, height, topm, true)
+	hr.Setup(numxtics, numxtics+2, width, leftm, false)
+	vr.Setup(numytics, numytics+1, height, topm, true)
 
 	if c.Title != "" {
 		drawTitle(g, c.Title, elementStyle(c.Options, TitleElement))
 	}
 
-	g.XAxis(c.XRange, topm+height, topm, c.Options)
-	g.YAxis(c.YRange, leftm, leftm+width, c.Options)
+	drawAnnotations(g, c.Annotations, BackgroundLayer, *hr, *vr, c.Options)
+	g.XAxis(*hr, topm+height, topm, c.Options)
+	g.YAxis(*vr, leftm, leftm+width, c.Options)
+	drawAnnotations(g, c.Annotations, BelowDataLayer, *hr, *vr, c.Options)
 
-	yf := c.YRange.Data2Screen
+	// Screen extent along the positions and the largest number of samples.
+	extent, maxN := width, 0
+	if c.Horizontal {
+		extent = height
+	}
+	for _, data := range c.Data {
+		for _, d := range data.Samples {
+			maxN = imax(maxN, d.N)
+		}
+	}
+
+	xf, yf := c.XRange.Data2Screen, c.YRange.Data2Screen
 	nan := math.NaN()
 	for _, data := range c.Data {
 		// Samples
 		nums := len(data.Samples)
-		bw := width / (2*nums - 1)
+		bw := extent / (2*nums - 1)
 
 		boxes := make([]Box, len(data.Samples))
 		for i, d := range data.Samples {
-			x := float64(c.XRange.Data2Screen(d.X))
+			x := float64(xf(d.X))
 			// DebugLogger.Printf("Q1=%.2f  Q3=%.3f", d.Q1, d.Q3)
 			q1, q3 := float64(yf(d.Q1)), float64(yf(d.Q3))
 			med, avg := nan, nan
@@ -154,7 +256,16 @@ func (c *BoxChart) Plot(g Graphics) {
 
 			outliers := make([]float64, len(d.Outliers))
 			for j, ol := range d.Outliers {
-				outliers[j] = float64(c.YRange.Data2Screen(ol))
+				outliers[j] = float64(yf(ol))
+			}
+			notchLow, notchHigh := d.NotchLow, d.NotchHigh
+			if c.Notched && notchLow == notchHigh && d.N > 0 {
+				notch := 1.57 * (d.Q3 - d.Q1) / math.Sqrt(float64(d.N))
+				notchLow, notchHigh = d.Med-notch, d.Med+notch
+			}
+			if c.Notched && notchLow != notchHigh && !math.IsNaN(med) {
+				boxes[i].NotchLow = float64(yf(notchLow))
+				boxes[i].NotchHigh = float64(yf(notchHigh))
 			}
 			boxes[i].X = x
 			boxes[i].Q1 = q1
@@ -164,10 +275,21 @@ func (c *BoxChart) Plot(g Graphics) {
 			boxes[i].High = high
 			boxes[i].Low = low
 			boxes[i].Outliers = outliers
+			boxes[i].N = d.N
+			boxes[i].Horizontal = c.Horizontal
+		}
+		if c.VarWidth && maxN > 0 {
+			for i, b := range boxes {
+				w := imax(1, int(float64(bw)*math.Sqrt(float64(b.N)/float64(maxN))+0.5))
+				g.Boxes(boxes[i:i+1], w, data.Style)
+			}
+		} else {
+			g.Boxes(boxes, bw, data.Style)
 		}
-		g.Boxes(boxes, bw, data.Style)
 	}
 
+	drawAnnotations(g, c.Annotations, AboveDataLayer, *hr, *vr, c.Options)
+
 	if !c.Key.Hide {
 		g.Key(layout.KeyX, layout.KeyY, c.Key, c.Options)
 	}
@@ -0,0 +1,38 @@
+package chart
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBoxWhiskers(t *testing.T) {
+	data := func() []float64 {
+		d := []float64{-40, 100}
+		for i := 1; i <= 20; i++ {
+			d = append(d, float64(i))
+		}
+		return d
+	}
+	for _, tc := range []struct {
+		c        BoxChart
+		outlier  bool
+		expected string
+	}{
+		{BoxChart{}, false, "-40 100 []"},
+		{BoxChart{}, true, "1 20 [-40 100]"},
+		{BoxChart{Whiskers: MinMaxWhiskers}, true, "-40 100 []"},
+		{BoxChart{WhiskerK: 0.5}, true, "1 20 [-40 100]"},
+		{BoxChart{WhiskerK: 0.1}, true, "4 17 [-40 1 2 3 18 19 20 100]"},
+		{BoxChart{Whiskers: PercentileWhiskers, WhiskerP: 10}, true, "2 19 [-40 1 20 100]"},
+	} {
+		c := tc.c
+		c.AddSet(0, data(), tc.outlier)
+		b := c.Data[0].Samples[0]
+		if got := fmt.Sprintf("%g %g %v", b.Low, b.High, b.Outliers); got != tc.expected {
+			t.Errorf("Whiskers=%d outlier=%t: Got %q, expected %q", tc.c.Whiskers, tc.outlier, got, tc.expected)
+		}
+		if b.N != 22 || b.NotchLow >= b.Med || b.NotchHigh <= b.Med {
+			t.Errorf("Bad N=%d or notch %g < %g < %g", b.N, b.NotchLow, b.Med, b.NotchHigh)
+		}
+	}
+}
@@ -68,6 +68,23 @@ const (
 	MirrorAxisOnly    MirrorAxis = 1  // just draw a mirrord axis, but omit tics
 )
 
+// LabelLayout is a set of strategies used to resolve overlapping tic labels
+// on an x axis. The enabled strategies are tried in the order rotate,
+// stagger, thin and truncate; the first one which resolves all collisions
+// is used. If none does, the last enabled strategy is applied anyway and
+// labels may still overprint each other (truncated labels always fit).
+type LabelLayout int
+
+const (
+	LabelRotate   LabelLayout = 1 << iota // rotate labels by 90 degree
+	LabelStagger                          // alternate labels on two rows
+	LabelThin                             // label only every n-th tic
+	LabelTruncate                         // shorten labels and append an ellipsis
+	LabelOverlap                          // keep labels, even if they overprint each other
+
+	LabelAuto = LabelRotate | LabelStagger | LabelThin | LabelTruncate // try all strategies
+)
+
 // TicSetting describes how (if at all) tics are shown on an axis.
 type TicSetting struct {
 	Hide       bool       // dont show tics if true
@@ -80,6 +97,7 @@ type TicSetting struct {
 	Mirror     MirrorAxis // 0: mirror axis and tics, -1: don't mirror anything, 1: mirror axis only (no tics)
 
 	// Format is used to print the tic labels. If unset FmtFloat is used.
+	// Use the Format method of a NumberFormat for localized labels.
 	Format func(float64) string
 
 	// TFormat is used to print tic labels for date/time axis.
@@ -89,6 +107,17 @@ type TicSetting struct {
 	// are printed.
 	TLocation *time.Location
 
+	// LabelLayout determines how colliding tic labels on an x axis are
+	// rearranged. Rearranging is opt-in: The zero value keeps all labels
+	// (like LabelOverlap) so existing charts look unchanged, LabelAuto
+	// tries all strategies.
+	LabelLayout LabelLayout
+
+	// TFiscalStart is the first month of the fiscal year. If set (and not
+	// January) autoscaled quarter, half year and year tics on date/time axis
+	// follow the fiscal calendar.
+	TFiscalStart time.Month
+
 	UserDelta bool // true if Delta or TDelta was input
 }
 
@@ -417,7 +446,7 @@ func (r *Range) tSetup(desiredNumberOfTics, maxNumberOfTics int, delta, mindelta
 		td = r.TicSetting.TDelta
 		r.TicSetting.UserDelta = true
 	} else {
-		td = MatchingTimeDelta(delta, 3)
+		td = FiscalTimeDelta(MatchingTimeDelta(delta, 3), r.TicSetting.TFiscalStart)
 		r.TicSetting.UserDelta = false
 	}
 	r.ShowLimits = true
@@ -442,7 +471,7 @@ func (r *Range) tSetup(desiredNumberOfTics, maxNumberOfTics int, delta, mindelta
 	if actNumTics > maxNumberOfTics {
 		// recalculate time tic delta
 		DebugLogger.Printf("Switching from %s no next larger step %s", td, NextTimeDelta(td))
-		td = NextTimeDelta(td)
+		td = FiscalTimeDelta(NextTimeDelta(td), r.TicSetting.TFiscalStart)
 		ftd = float64(td.Seconds())
 		r.TMin, ftic = tApplyRangeMode(r.MinMode, mint, td, false)
 		r.TMax, ltic = tApplyRangeMode(r.MaxMode, maxt, td, true)
@@ -674,8 +703,12 @@ type LayoutData struct {
 	NumXtics, NumYtics int // suggested numer of tics for both axis
 }
 
-// Layout graph data area on screen and place key.
-func layout(g Graphics, title, xlabel, ylabel string, hidextics, hideytics bool, key *Key) (ld LayoutData) {
+// Layout graph data area on screen and place key. The margins around the
+// graph area are derived from the measured size of the title, the axis
+// labels, an outside key and the tic labels of xr and yr. To measure the
+// tic labels the ranges are set up on copies, xr and yr are not modified.
+// A nil range is not drawn at all.
+func layout(g Graphics, title string, xr, yr *Range, key *Key, options PlotOptions) (ld LayoutData) {
 	fw, fh, _ := g.FontMetrics(Font{})
 	w, h := g.Dimensions()
 
@@ -683,108 +716,172 @@ func layout(g Graphics, title, xlabel, ylabel string, hidextics, hideytics bool,
 		key.Pos = "itr"
 	}
 
-	width, leftm, height, topm := w-int(6*fw), int(2*fw), h-2*fh, fh
-	xlabsep, ylabsep := fh, int(3*fw)
-	if title != "" {
-		topm += (5 * fh) / 2
-		height -= (5 * fh) / 2
-	}
-	if xlabel != "" {
-		height -= (3 * fh) / 2
-	}
-	if !hidextics {
-		height -= (3 * fh) / 2
-		xlabsep += (3 * fh) / 2
-	}
-	if ylabel != "" {
-		leftm += 2 * fh
-		width -= 2 * fh
-	}
-	if !hideytics {
-		leftm += int(6 * fw)
-		width -= int(6 * fw)
-		ylabsep += int(6 * fw)
-	}
-
-	if key != nil && !key.Hide && len(key.Place()) > 0 {
-		m := key.Place()
-		kw, kh, _, _ := key.Layout(g, m, Font{}) // TODO: use real font
-		sepx, sepy := int(fw)+fh, int(fw)+fh
-		switch key.Pos[:2] {
-		case "ol":
-			width, leftm = width-kw-sepx, leftm+kw
-			ld.KeyX = sepx / 2
-		case "or":
-			width = width - kw - sepx
-			ld.KeyX = w - kw - sepx/2
-		case "ot":
-			height, topm = height-kh-sepy, topm+kh
-			ld.KeyY = sepy / 2
-			if title != "" {
-				ld.KeyY += 2 * fh
-			}
-		case "ob":
-			height = height - kh - sepy
-			ld.KeyY = h - kh - sepy/2
-		case "it":
-			ld.KeyY = topm + sepy
-		case "ic":
-			ld.KeyY = topm + (height-kh)/2
-		case "ib":
-			ld.KeyY = topm + height - kh - sepy
-
-		}
-
-		switch key.Pos[:2] {
-		case "ol", "or":
-			switch key.Pos[2] {
-			case 't':
-				ld.KeyY = topm
-			case 'c':
+	ticfont := elementStyle(options, MajorTicElement).Font
+	_, tfh, _ := g.FontMetrics(ticfont)
+	_, afh, _ := g.FontMetrics(elementStyle(options, MajorAxisElement).Font)
+	_, lfh, _ := g.FontMetrics(elementStyle(options, RangeLimitElement).Font)
+	_, th, _ := g.FontMetrics(elementStyle(options, TitleElement).Font)
+	lfh = imax(lfh, afh)
+
+	// Distance of tic labels from the axis, see GenericXAxis and GenericYAxis.
+	// Character cell output places tic labels right next to the axis.
+	xticsep, yticsep := 2*imin(12, imax(4, tfh/2)), 2*imin(10, imax(4, afh/2))
+	if fh <= 1 {
+		xticsep, yticsep = 1, 2
+	}
+
+	var xlabel, ylabel string
+	var showxtics, showytics, showlimits bool
+	if xr != nil {
+		xlabel, showlimits = xr.Label, xr.ShowLimits
+		showxtics = !xr.TicSetting.Hide && !xr.TicSetting.HideLabels
+	}
+	if yr != nil {
+		ylabel = yr.Label
+		showytics = !yr.TicSetting.Hide && !yr.TicSetting.HideLabels
+		showytics = showytics && yr.DataMin <= yr.DataMax
+	}
+
+	// Width of y tic labels, height of x tic labels and overhang of last
+	// x tic label: Start with estimates and measure the real tic labels
+	// in a second pass.
+	ytw, xth, xover := int(4*fw), tfh, 0
+	for pass := 0; ; pass++ {
+		topm := fh
+		if title != "" {
+			topm = th/3 + th + fh
+		}
+		bottom := fh / 2
+		if showxtics {
+			bottom += xticsep + xth
+		}
+		if xlabel != "" || showlimits {
+			bottom += fh/2 + lfh
+		}
+		bottom = imax(bottom+fh/2, fh)
+		leftm := int(2 * fw)
+		if ylabel != "" {
+			leftm = 2*afh + int(fw)
+		}
+		if showytics {
+			leftm += ytw + yticsep
+		}
+		right := imax(int(2*fw), xover+int(fw))
+		width, height := w-leftm-right, h-topm-bottom
+
+		if key != nil && !key.Hide && len(key.Place()) > 0 {
+			m := key.Place()
+			kw, kh, _, _ := key.Layout(g, m, elementStyle(options, KeyElement).Font)
+			sepx, sepy := int(fw)+fh, int(fw)+fh
+			switch key.Pos[:2] {
+			case "ol":
+				width, leftm = width-kw-sepx, leftm+kw
+				ld.KeyX = sepx / 2
+			case "or":
+				width = width - kw - sepx
+				ld.KeyX = w - kw - sepx/2
+			case "ot":
+				height, topm = height-kh-sepy, topm+kh
+				ld.KeyY = sepy / 2
+				if title != "" {
+					ld.KeyY += th/3 + th
+				}
+			case "ob":
+				height = height - kh - sepy
+				ld.KeyY = h - kh - sepy/2
+			case "it":
+				ld.KeyY = topm + sepy
+			case "ic":
 				ld.KeyY = topm + (height-kh)/2
-			case 'b':
-				ld.KeyY = topm + height - kh
+			case "ib":
+				ld.KeyY = topm + height - kh - sepy
+
 			}
-		case "ot", "ob":
-			switch key.Pos[2] {
-			case 'l':
-				ld.KeyX = leftm
-			case 'c':
-				ld.KeyX = leftm + (width-kw)/2
-			case 'r':
-				ld.KeyX = w - kw - sepx
+
+			switch key.Pos[:2] {
+			case "ol", "or":
+				switch key.Pos[2] {
+				case 't':
+					ld.KeyY = topm
+				case 'c':
+					ld.KeyY = topm + (height-kh)/2
+				case 'b':
+					ld.KeyY = topm + height - kh
+				}
+			case "ot", "ob":
+				switch key.Pos[2] {
+				case 'l':
+					ld.KeyX = leftm
+				case 'c':
+					ld.KeyX = leftm + (width-kw)/2
+				case 'r':
+					ld.KeyX = w - kw - sepx
+				}
+			}
+			if key.Pos[0] == 'i' {
+				switch key.Pos[2] {
+				case 'l':
+					ld.KeyX = leftm + sepx
+				case 'c':
+					ld.KeyX = leftm + (width-kw)/2
+				case 'r':
+					ld.KeyX = leftm + width - kw - sepx
+				}
 			}
 		}
-		if key.Pos[0] == 'i' {
-			switch key.Pos[2] {
-			case 'l':
-				ld.KeyX = leftm + sepx
-			case 'c':
-				ld.KeyX = leftm + (width-kw)/2
-			case 'r':
-				ld.KeyX = leftm + width - kw - sepx
+
+		// Number of tics
+		if width/int(fw) <= 20 {
+			ld.NumXtics = 2
+		} else {
+			ld.NumXtics = width / int(10*fw)
+			if ld.NumXtics > 25 {
+				ld.NumXtics = 25
 			}
 		}
-	}
+		ld.NumYtics = height / (4 * fh)
+		if ld.NumYtics > 20 {
+			ld.NumYtics = 20
+		}
 
-	// fmt.Printf("width=%d, height=%d, leftm=%d, topm=%d  (fw=%d)\n", width, height, leftm, topm, int(fw))
+		ld.Width, ld.Height = width, height
+		ld.Left, ld.Top = leftm, topm
 
-	// Number of tics
-	if width/int(fw) <= 20 {
-		ld.NumXtics = 2
-	} else {
-		ld.NumXtics = width / int(10*fw)
-		if ld.NumXtics > 25 {
-			ld.NumXtics = 25
+		if pass == 1 || (!showxtics && !showytics) {
+			break
 		}
-	}
-	ld.NumYtics = height / (4 * fh)
-	if ld.NumYtics > 20 {
-		ld.NumYtics = 20
-	}
 
-	ld.Width, ld.Height = width, height
-	ld.Left, ld.Top = leftm, topm
+		// Measure the tic labels of the ranges set up for this layout.
+		if showytics {
+			yc := *yr
+			yc.Setup(ld.NumYtics, ld.NumYtics+2, height, topm, true)
+			ytw = 0
+			for _, tic := range yc.Tics {
+				ytw = imax(ytw, g.TextLen(tic.Label, ticfont))
+			}
+		}
+		if showxtics {
+			xc := *xr
+			xc.Setup(ld.NumXtics, ld.NumXtics+2, width, leftm, false)
+			lp := PlaceXTicLabels(g, xc, ticfont)
+			xth, xover, showlimits = lp.Height, 0, showlimits || xc.ShowLimits
+			for i := len(lp.Tics) - 1; i >= 0; i-- {
+				tic := lp.Tics[i]
+				if tic.Label == "" {
+					continue
+				}
+				lw := g.TextLen(tic.Label, ticfont)
+				right := xc.Data2Screen(tic.LabelPos) + lw/2
+				if lp.Rot != 0 {
+					right = xc.Data2Screen(tic.LabelPos) + tfh/2
+				} else if xc.Time && tic.Align == -1 {
+					right += lw - lw/2
+				}
+				xover = imax(0, right-(leftm+width))
+				break
+			}
+		}
+	}
 
 	return
 }
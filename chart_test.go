@@ -0,0 +1,119 @@
+package chart_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/vdobler/chart"
+	"github.com/vdobler/chart/txtg"
+)
+
+// screenArea reports the screen interval covered by the x and y range,
+// their number of tics and the upper end of the y range after plotting.
+func screenArea(xr, yr chart.Range) string {
+	return fmt.Sprintf("x %d-%d y %d-%d tics %d %d ymax %g",
+		xr.Data2Screen(xr.Min), xr.Data2Screen(xr.Max),
+		yr.Data2Screen(yr.Max), yr.Data2Screen(yr.Min),
+		len(xr.Tics), len(yr.Tics), yr.Max)
+}
+
+func TestLayout(t *testing.T) {
+	x := []float64{0, 1, 2, 3, 4}
+
+	scatter := func(title string, key bool) func(g chart.Graphics) string {
+		return func(g chart.Graphics) string {
+			c := chart.ScatterChart{Title: title}
+			c.XRange.Label, c.YRange.Label = "X", "Y"
+			c.Key.Hide = !key
+			c.Key.Pos = "orc"
+			c.AddDataPair("Data", x, []float64{10, 250, 1200, 300, 40}, chart.PlotStylePoints, chart.Style{})
+			c.Plot(g)
+			return screenArea(c.XRange, c.YRange)
+		}
+	}
+	stacked := func(g chart.Graphics) string {
+		c := chart.BarChart{Stacked: true}
+		c.Key.Hide = true
+		c.AddDataPair("A", x, []float64{20, 30, 40, 30, 20}, chart.AutoStyle(0, true))
+		c.AddDataPair("B", x, []float64{50, 60, 70, 60, 50}, chart.AutoStyle(1, true))
+		c.Plot(g)
+		return screenArea(c.XRange, c.YRange)
+	}
+	hist := func(g chart.Graphics) string {
+		c := chart.HistChart{Counts: true}
+		c.XRange.Label = "Value"
+		c.Key.Hide = true
+		c.AddData("A", []float64{0.5, 1.5, 1.5, 2.5, 2.5, 2.5, 3.5, 3.5, 4.5}, chart.Style{})
+		c.Plot(g)
+		return screenArea(c.XRange, c.YRange)
+	}
+
+	for i, tc := range []struct {
+		plot     func(g chart.Graphics) string
+		expected string
+	}{
+		{scatter("", false), "x 10-78 y 1-22 tics 7 9 ymax 1400"},
+		{scatter("Title", false), "x 10-78 y 2-22 tics 7 9 ymax 1400"},
+		{scatter("Title", true), "x 10-61 y 2-22 tics 7 9 ymax 1400"},
+		{stacked, "x 9-78 y 1-22 tics 11 7 ymax 120"},
+		{hist, "x 10-78 y 1-21 tics 11 8 ymax 3.5"},
+	} {
+		g := txtg.New(80, 25)
+		if got := tc.plot(g); got != tc.expected {
+			t.Errorf("%d: got %q, expected %q\n%s", i, got, tc.expected, g)
+		}
+	}
+}
+
+func TestWaterfallRange(t *testing.T) {
+	c := chart.WaterfallChart{}
+	c.Key.Hide = true
+	c.AddStart("Start", 10)
+	c.AddStep("Up", 5)
+	c.AddTotal("End")
+	c.Plot(txtg.New(60, 20))
+	if c.YRange.Min != 0 || c.YRange.MinMode.Fixed {
+		t.Errorf("positive: y axis starts at %g, fixed %t", c.YRange.Min, c.YRange.MinMode.Fixed)
+	}
+
+	// Plotting again after the data went negative must extend the axis.
+	c.AddStep("Down", -25)
+	c.AddTotal("Net")
+	c.Plot(txtg.New(60, 20))
+	if c.YRange.Min >= -10 || c.YRange.MinMode.Fixed || c.YRange.MaxMode.Fixed {
+		t.Errorf("mixed: y axis %g to %g, fixed %t %t", c.YRange.Min, c.YRange.Max,
+			c.YRange.MinMode.Fixed, c.YRange.MaxMode.Fixed)
+	}
+
+	// A fixed range of the user is kept.
+	c.YRange.MinMode = chart.RangeMode{Fixed: true, Value: -50}
+	c.Plot(txtg.New(60, 20))
+	if c.YRange.Min != -50 {
+		t.Errorf("fixed: y axis starts at %g", c.YRange.Min)
+	}
+}
+
+func TestBarPercentRange(t *testing.T) {
+	c := chart.BarChart{Stacked: true, Percent: true}
+	c.Key.Hide = true
+	c.AddDataPair("A", []float64{0, 1}, []float64{100, 300}, chart.AutoStyle(0, true))
+	c.AddDataPair("B", []float64{0, 1}, []float64{200, 300}, chart.AutoStyle(1, true))
+	c.Plot(txtg.New(60, 20))
+	if c.YRange.Max != 100 || c.YRange.MaxMode.Fixed {
+		t.Errorf("percent: y axis ends at %g, fixed %t", c.YRange.Max, c.YRange.MaxMode.Fixed)
+	}
+
+	// Absolute stacks after switching Percent off must not be clipped.
+	c.Percent = false
+	c.Plot(txtg.New(60, 20))
+	if c.YRange.Max < 600 || c.YRange.MaxMode.Fixed {
+		t.Errorf("absolute: y axis ends at %g, fixed %t", c.YRange.Max, c.YRange.MaxMode.Fixed)
+	}
+
+	// And back again.
+	c.Percent = true
+	c.Plot(txtg.New(60, 20))
+	if c.YRange.Max != 100 {
+		t.Errorf("percent again: y axis ends at %g", c.YRange.Max)
+	}
+}
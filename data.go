@@ -91,11 +91,14 @@ func (c CatValue) Flaged() bool     { return c.Flag }
 
 // Box represents a box in an boxplot.
 type Box struct {
-	X           float64   // x-position of the box
-	Avg         float64   // "average" value (uncommon in std. box plots, but sometimes useful)
-	Q1, Med, Q3 float64   // lower quartil, median and upper quartil
-	Low, High   float64   // low and hig end of whiskers (normaly last point in the 1.5*IQR range of Q1/3)
-	Outliers    []float64 // list of y-values of outliers
+	X                   float64   // x-position of the box
+	Avg                 float64   // "average" value (uncommon in std. box plots, but sometimes useful)
+	Q1, Med, Q3         float64   // lower quartil, median and upper quartil
+	Low, High           float64   // low and hig end of whiskers (normaly last point in the 1.5*IQR range of Q1/3)
+	Outliers            []float64 // list of y-values of outliers
+	N                   int       // number of samples (0: unknown), used for notches and box widths
+	NotchLow, NotchHigh float64   // confidence interval of the median, drawn as notch if different
+	Horizontal          bool      // box is drawn horizontally: X is the vertical position, the values are horizontal
 }
 
 func (p Box) XVal() float64 { return p.X }
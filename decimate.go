@@ -0,0 +1,133 @@
+package chart
+
+import (
+	"math"
+)
+
+// Decimation determines how ScatterChart reduces long series before drawing.
+// Decimation works on screen coordinates after mapping the data to the
+// plot area: A series with more points than pixels shows no more detail
+// but is much slower to draw.
+type Decimation int
+
+const (
+	AutoDecimation   Decimation = iota // MinMaxDecimation for pure lines (PlotStyleLines) without error bars, else none
+	NoDecimation                       // draw all points
+	MinMaxDecimation                   // keep first, last, minimum and maximum point per pixel column
+	LTTBDecimation                     // Largest-Triangle-Three-Buckets down to DecimationLimit points
+)
+
+// decimate reduces points (in screen coordinates) of a series drawn with
+// plotstyle according to mode if there are more than limit points.
+func decimate(points []EPoint, plotstyle PlotStyle, mode Decimation, limit int) []EPoint {
+	if len(points) <= limit {
+		return points
+	}
+	switch mode {
+	case AutoDecimation:
+		if plotstyle != PlotStyleLines {
+			return points // dropped points would lose their markers
+		}
+		for _, p := range points {
+			if !math.IsNaN(p.DeltaX) || !math.IsNaN(p.DeltaY) {
+				return points
+			}
+		}
+		return MinMaxDecimate(points)
+	case MinMaxDecimation:
+		return MinMaxDecimate(points)
+	case LTTBDecimation:
+		// Each line between breaks (see lineBreak) gets its share of limit.
+		out := make([]EPoint, 0, limit)
+		start := 0
+		for i := 0; i <= len(points); i++ {
+			if i < len(points) && !math.IsNaN(points[i].X) {
+				continue
+			}
+			line := points[start:i]
+			out = append(out, LTTB(line, imax(3, limit*len(line)/len(points)))...)
+			if i < len(points) {
+				out = append(out, points[i])
+			}
+			start = i + 1
+		}
+		return out
+	}
+	return points
+}
+
+// MinMaxDecimate reduces consecutive points in the same pixel column
+// (points[i].X rounded down) to at most four: The first, the lowest, the
+// highest and the last one, in their original order. Drawn as lines the
+// result is indistinguishable from the full series.
+func MinMaxDecimate(points []EPoint) []EPoint {
+	out := make([]EPoint, 0, imin(len(points), 1024))
+	for start := 0; start < len(points); {
+		col := math.Floor(points[start].X)
+		end, lo, hi := start+1, start, start
+		for end < len(points) && math.Floor(points[end].X) == col {
+			if points[end].Y < points[lo].Y {
+				lo = end
+			}
+			if points[end].Y > points[hi].Y {
+				hi = end
+			}
+			end++
+		}
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		prev := -1
+		for _, i := range []int{start, lo, hi, end - 1} {
+			if i != prev {
+				out = append(out, points[i])
+				prev = i
+			}
+		}
+		start = end
+	}
+	return out
+}
+
+// LTTB reduces points to n points by the Largest-Triangle-Three-Buckets
+// algorithm (Steinarsson 2013): The first and the last point are kept and
+// from each of n-2 buckets of consecutive points the one spanning the
+// largest triangle with the previously selected point and the average of
+// the next bucket. Peaks and the overall shape are preserved.
+func LTTB(points []EPoint, n int) []EPoint {
+	if n >= len(points) || n < 3 {
+		return points
+	}
+	out := make([]EPoint, 0, n)
+	out = append(out, points[0])
+	size := float64(len(points)-2) / float64(n-2)
+	a := 0 // the previously selected point
+	for b := 0; b < n-2; b++ {
+		from, to := int(float64(b)*size)+1, int(float64(b+1)*size)+1
+
+		// Average of the next bucket (the last point for the last bucket).
+		nfrom, nto := to, imin(int(float64(b+2)*size)+1, len(points))
+		if b == n-3 {
+			nfrom, nto = len(points)-1, len(points)
+		}
+		var ax, ay float64
+		for i := nfrom; i < nto; i++ {
+			ax += points[i].X
+			ay += points[i].Y
+		}
+		ax /= float64(nto - nfrom)
+		ay /= float64(nto - nfrom)
+
+		best, area := from, -1.0
+		pa := points[a]
+		for i := from; i < to; i++ {
+			ar := math.Abs((pa.X-ax)*(points[i].Y-pa.Y) - (pa.X-points[i].X)*(ay-pa.Y))
+			if ar > area {
+				best, area = i, ar
+			}
+		}
+		out = append(out, points[best])
+		a = best
+	}
+	return append(out, points[len(points)-1])
+}
@@ -0,0 +1,108 @@
+package chart_test
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/vdobler/chart"
+	"github.com/vdobler/chart/imgg"
+	"github.com/vdobler/chart/txtg"
+)
+
+func screenPoints(xy ...float64) []chart.EPoint {
+	points := make([]chart.EPoint, len(xy)/2)
+	for i := range points {
+		points[i] = chart.EPoint{X: xy[2*i], Y: xy[2*i+1], DeltaX: math.NaN(), DeltaY: math.NaN()}
+	}
+	return points
+}
+
+func xy(points []chart.EPoint) string {
+	s := ""
+	for _, p := range points {
+		s += fmt.Sprintf(" %g,%g", p.X, p.Y)
+	}
+	return s
+}
+
+func TestMinMaxDecimate(t *testing.T) {
+	points := screenPoints(0, 5, 0.2, 1, 0.4, 9, 0.6, 4, 0.8, 6, 1, 3, 2, 7, 2.5, 7, 3, 1, 3.5, 0)
+	if got, want := xy(chart.MinMaxDecimate(points)), " 0,5 0.2,1 0.4,9 0.8,6 1,3 2,7 2.5,7 3,1 3.5,0"; got != want {
+		t.Errorf("Got %s, expected %s", got, want)
+	}
+}
+
+func TestLTTB(t *testing.T) {
+	points := screenPoints(0, 0, 1, 1, 2, 0, 3, 0, 4, 10, 5, 0, 6, 0, 7, 1, 8, 0)
+	if got, want := xy(chart.LTTB(points, 4)), " 0,0 3,0 4,10 8,0"; got != want {
+		t.Errorf("Got %s, expected %s", got, want)
+	}
+	if got := chart.LTTB(points, 20); len(got) != len(points) {
+		t.Errorf("Got %d points", len(got))
+	}
+}
+
+// scatterRecorder records the number of points passed to Scatter.
+type scatterRecorder struct {
+	*txtg.TextGraphics
+	counts []int
+}
+
+func (r *scatterRecorder) Scatter(points []chart.EPoint, plotstyle chart.PlotStyle, style chart.Style) {
+	r.counts = append(r.counts, len(points))
+	r.TextGraphics.Scatter(points, plotstyle, style)
+}
+
+func TestAutoDecimation(t *testing.T) {
+	// Only pure lines are decimated: Markers of dropped points would be missing.
+	for _, tc := range []struct {
+		plotstyle  chart.PlotStyle
+		decimation chart.Decimation
+		decimated  bool
+	}{
+		{chart.PlotStyleLines, chart.AutoDecimation, true},
+		{chart.PlotStyleLinesPoints, chart.AutoDecimation, false},
+		{chart.PlotStylePoints, chart.AutoDecimation, false},
+		{chart.PlotStyleLinesPoints, chart.MinMaxDecimation, true},
+		{chart.PlotStyleLines, chart.NoDecimation, false},
+	} {
+		c := bigScatterChart(1000, tc.decimation)
+		c.Data[0].PlotStyle = tc.plotstyle
+		g := &scatterRecorder{TextGraphics: txtg.New(60, 20)}
+		c.Plot(g)
+		if decimated := g.counts[0] < 1000; decimated != tc.decimated {
+			t.Errorf("plotstyle %d, decimation %d: drew %d points", tc.plotstyle, tc.decimation, g.counts[0])
+		}
+	}
+}
+
+func bigScatterChart(n int, mode chart.Decimation) *chart.ScatterChart {
+	rng := rand.New(rand.NewSource(1))
+	x, y := make([]float64, n), make([]float64, n)
+	for i := range x {
+		x[i] = float64(i)
+		y[i] = math.Sin(float64(i)/float64(n)*20) + 0.2*rng.NormFloat64()
+	}
+	c := &chart.ScatterChart{Title: "Large Series", Decimation: mode}
+	c.AddDataPair("Data", x, y, chart.PlotStyleLines,
+		chart.Style{LineColor: color.NRGBA{0x00, 0x00, 0xcc, 0xff}, LineWidth: 1, LineStyle: chart.SolidLine})
+	return c
+}
+
+func benchmarkScatterPlot(b *testing.B, mode chart.Decimation) {
+	c := bigScatterChart(1000000, mode)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Reset()
+		c.Plot(imgg.New(800, 600, color.RGBA{0xff, 0xff, 0xff, 0xff}, nil, nil))
+	}
+}
+
+func BenchmarkScatterPlotNoDecimation(b *testing.B) { benchmarkScatterPlot(b, chart.NoDecimation) }
+func BenchmarkScatterPlotMinMaxDecimation(b *testing.B) {
+	benchmarkScatterPlot(b, chart.MinMaxDecimation)
+}
+func BenchmarkScatterPlotLTTBDecimation(b *testing.B) { benchmarkScatterPlot(b, chart.LTTBDecimation) }
@@ -56,6 +56,11 @@ How the axis is autoscaled can be controlled for both ends of the axis
 individually by MinMode and MaxMode which allow a fine control of the
 (auto-) scaling.
 
+All axis based charts may be decorated with Annotations: free text, arrows
+pointing to data points, horizontal or vertical reference lines and shaded
+spans.  Each annotation is drawn in one of the layers below the grid, between
+grid and data or on top of the data.
+
 After setting up the chart, adding data, samples, functions you can render
 the chart to a Graphics output.  This process will set several internal
 fields of the chart.  If you reuse the chart, add additional data and
@@ -0,0 +1,76 @@
+package chart
+
+import (
+	"math"
+	"sort"
+)
+
+// ECDF returns the empirical cumulative distribution function of data as
+// the corners of a step function: The fraction of samples <= x jumps at
+// each distinct sample value. NaN samples are ignored.
+func ECDF(data []float64) []EPoint {
+	return ecdfSteps(sortedSamples(data), 0)
+}
+
+// sortedSamples returns the non-NaN values of data in ascending order.
+func sortedSamples(data []float64) []float64 {
+	sorted := make([]float64, 0, len(data))
+	for _, v := range data {
+		if !math.IsNaN(v) {
+			sorted = append(sorted, v)
+		}
+	}
+	sort.Float64s(sorted)
+	return sorted
+}
+
+// ecdfSteps returns the step function of the sorted data shifted by delta
+// and clipped to [0,1].
+func ecdfSteps(sorted []float64, delta float64) []EPoint {
+	n := len(sorted)
+	if n == 0 {
+		return nil
+	}
+	nan := math.NaN()
+	point := func(x, f float64) EPoint {
+		return EPoint{X: x, Y: math.Max(0, math.Min(1, f+delta)), DeltaX: nan, DeltaY: nan}
+	}
+	points := make([]EPoint, 0, 2*n)
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && sorted[j] == sorted[i] {
+			j++
+		}
+		points = append(points, point(sorted[i], float64(i)/float64(n)), point(sorted[i], float64(j)/float64(n)))
+		i = j
+	}
+	return points
+}
+
+// AddECDF adds the empirical cumulative distribution function of data as a
+// step line to c. If level is in (0,1) the confidence band of this level
+// (e.g. 0.95) according to the Dvoretzky-Kiefer-Wolfowitz inequality is
+// added as two dotted step lines. A key entry is produced if name is not
+// empty; an empty style is generated by AutoStyle (with a line width of 2).
+func (c *ScatterChart) AddECDF(name string, data []float64, level float64, style Style) {
+	if style.empty() {
+		style = AutoStyle(len(c.Data), false)
+		style.LineWidth = 2
+	}
+	if style.Symbol == 0 {
+		style.Symbol = '*'
+	}
+	sorted := sortedSamples(data)
+	c.AddData(name, ecdfSteps(sorted, 0), PlotStyleLines, style)
+	if level <= 0 || level >= 1 || len(sorted) == 0 {
+		return
+	}
+	eps := math.Sqrt(math.Log(2/(1-level)) / (2 * float64(len(sorted))))
+	band := style
+	band.Symbol, band.LineStyle, band.LineWidth = '.', DottedLine, 1
+	if band.LineColor == nil {
+		band.LineColor = style.SymbolColor
+	}
+	c.AddData("", ecdfSteps(sorted, -eps), PlotStyleLines, band)
+	c.AddData("", ecdfSteps(sorted, eps), PlotStyleLines, band)
+}
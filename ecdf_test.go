@@ -0,0 +1,34 @@
+package chart
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestECDF(t *testing.T) {
+	got := ""
+	for _, p := range ECDF([]float64{3, 1, math.NaN(), 2, 2}) {
+		got += fmt.Sprintf(" %g,%g", p.X, p.Y)
+	}
+	if want := " 1,0 1,0.25 2,0.25 2,0.75 3,0.75 3,1"; got != want {
+		t.Errorf("Got %s, expected %s", got, want)
+	}
+
+	c := ScatterChart{}
+	data := make([]float64, 20)
+	for i := range data {
+		data[i] = float64(i)
+	}
+	c.AddECDF("", data, 0.95, Style{})
+	if len(c.Data) != 3 {
+		t.Fatalf("Got %d data sets", len(c.Data))
+	}
+	eps := math.Sqrt(math.Log(40) / 40)
+	if lo, hi := c.Data[1].Samples[19].Y, c.Data[2].Samples[19].Y; math.Abs(lo-(0.5-eps)) > 1e-12 || math.Abs(hi-(0.5+eps)) > 1e-12 {
+		t.Errorf("Got band %g .. %g", lo, hi)
+	}
+	if c.Data[1].Samples[0].Y != 0 || c.Data[2].Samples[39].Y != 1 {
+		t.Errorf("Band not clipped to [0,1]")
+	}
+}
@@ -31,6 +31,9 @@ var (
 
 var Background = color.RGBA{0xff, 0xff, 0xff, 0xff}
 
+// Braille selects the high resolution text driver for the text output.
+var Braille bool
+
 // -------------------------------------------------------------------------
 // Dumper
 
@@ -90,7 +93,15 @@ func (d *Dumper) Plot(c chart.Chart) {
 	sgr := svgg.AddTo(d.S, col*d.W, row*d.H, d.W, d.H, "", 12, color.RGBA{0xff, 0xff, 0xff, 0xff})
 	c.Plot(sgr)
 
-	tgr := txtg.New(100, 30)
+	var tgr interface {
+		chart.Graphics
+		String() string
+	}
+	if Braille {
+		tgr = txtg.NewBraille(100, 30)
+	} else {
+		tgr = txtg.New(100, 30)
+	}
 	c.Plot(tgr)
 	d.txtFile.Write([]byte(tgr.String() + "\n\n\n"))
 
@@ -284,6 +295,98 @@ func scatterChart() {
 	dumper.Plot(&pl)
 }
 
+func fitChart() {
+	dumper := NewDumper("xfit", 2, 2, 400, 300)
+	defer dumper.Close()
+
+	x := make([]float64, 40)
+	lin, quad, growth, wave := make([]float64, 40), make([]float64, 40), make([]float64, 40), make([]float64, 40)
+	for i := range x {
+		x[i] = float64(i) / 4
+		lin[i] = 3 + 1.5*x[i] + 2*rand.NormFloat64()
+		quad[i] = 10 - 4*x[i] + 0.5*x[i]*x[i] + rand.NormFloat64()
+		growth[i] = 2 * math.Exp(0.3*x[i]) * (1 + 0.1*rand.NormFloat64())
+		wave[i] = 5*math.Sin(x[i]) + x[i] + rand.NormFloat64()
+	}
+	blue := chart.Style{Symbol: 'o', SymbolColor: color.NRGBA{0x00, 0x00, 0xcc, 0xff}}
+	for _, f := range []struct {
+		title string
+		y     []float64
+		fit   chart.Fit
+	}{
+		{"Linear with 95% Band", lin, chart.Fit{Model: chart.LinearFit, Confidence: 0.95, Equation: true}},
+		{"Polynomial", quad, chart.Fit{Model: chart.PolynomialFit, Degree: 2, Equation: true}},
+		{"Exponential", growth, chart.Fit{Model: chart.ExponentialFit, Equation: true}},
+		{"LOESS", wave, chart.Fit{Model: chart.LoessFit, Span: 0.3, Equation: true}},
+	} {
+		sc := chart.ScatterChart{Title: f.title}
+		sc.XRange.Label, sc.YRange.Label = "X", "Y"
+		sc.Key.Pos = "obc"
+		sc.AddDataPair("Data", x, f.y, chart.PlotStylePoints, blue)
+		sc.AddFit("", 0, f.fit, chart.Style{})
+		dumper.Plot(&sc)
+	}
+}
+
+func decimationChart() {
+	dumper := NewDumper("xdecimate", 1, 3, 600, 250)
+	defer dumper.Close()
+
+	n := 200000
+	x, y := make([]float64, n), make([]float64, n)
+	for i := range x {
+		x[i] = float64(i)
+		y[i] = math.Sin(float64(i)/float64(n)*20) + 0.2*rand.NormFloat64()
+		if i%50000 == 25000 {
+			y[i] += 3 // single spikes must survive decimation
+		}
+	}
+	for _, d := range []struct {
+		title string
+		mode  chart.Decimation
+	}{
+		{"200000 Points, no Decimation", chart.NoDecimation},
+		{"Min/Max Decimation", chart.MinMaxDecimation},
+		{"LTTB Decimation", chart.LTTBDecimation},
+	} {
+		c := chart.ScatterChart{Title: d.title, Decimation: d.mode}
+		c.Key.Hide = true
+		c.AddDataPair("Data", x, y, chart.PlotStyleLines,
+			chart.Style{Symbol: '*', LineColor: color.NRGBA{0x00, 0x00, 0xcc, 0xff}, LineWidth: 1, LineStyle: chart.SolidLine})
+		dumper.Plot(&c)
+	}
+}
+
+//
+// Annotations: text, arrows, reference lines and spans
+//
+func annotations() {
+	dumper := NewDumper("xannotation", 1, 1, 800, 600)
+	defer dumper.Close()
+
+	pl := chart.ScatterChart{Title: "Response Time"}
+	pl.XRange.Label, pl.YRange.Label = "Hour", "Latency [ms]"
+	pl.YRange.TicSetting.Grid = chart.GridLines
+	x := make([]float64, 48)
+	y := make([]float64, 48)
+	for i := range x {
+		x[i] = float64(i) / 2
+		y[i] = 120 + 40*math.Sin(x[i]/4) + 15*rand.NormFloat64()
+	}
+	y[30] = 290
+	pl.AddDataPair("p99", x, y, chart.PlotStyleLinesPoints, chart.Style{})
+
+	pl.Annotations.AddYSpan(200, 320, "SLO violated", chart.Style{})
+	pl.Annotations.AddHLine(200, "SLO 200 ms", chart.Style{})
+	pl.Annotations.AddXSpan(9, 11, "maintenance", chart.Style{})
+	pl.Annotations.AddVLine(4, "deploy v1.2", chart.Style{})
+	pl.Annotations.AddArrow(x[30], y[30], 10, 280, "GC pause", chart.Style{})
+	pl.Annotations.AddText(23.5, 60, "generated", chart.Style{})
+	pl.Annotations[len(pl.Annotations)-1].Align = "br"
+
+	dumper.Plot(&pl)
+}
+
 //
 // Function plots with fancy clippings
 //
@@ -454,6 +557,107 @@ func boxChart() {
 
 }
 
+// Box chart options: whisker rules, notches, variable width and horizontal boxes
+func boxOptionsChart() {
+	dumper := NewDumper("xbox2", 2, 2, 400, 300)
+	defer dumper.Close()
+
+	sets := [][]float64{
+		bigauss(150, 10, 5, 20, 5, 45, 0, 60),
+		bigauss(40, 5, 6, 28, 3, 5, 0, 60),
+		bigauss(400, 0, 4, 24, 0, 0, 0, 60),
+	}
+	style := chart.Style{Symbol: 'o', LineColor: color.NRGBA{0x00, 0x00, 0xcc, 0xff}, LineWidth: 1, LineStyle: chart.SolidLine,
+		FillColor: color.NRGBA{0xb0, 0xc0, 0xff, 0xff}}
+	for _, opt := range []struct {
+		title string
+		set   func(c *chart.BoxChart)
+	}{
+		{"Notched Boxes", func(c *chart.BoxChart) { c.Notched = true }},
+		{"5-95 Percentile Whiskers", func(c *chart.BoxChart) { c.Whiskers, c.WhiskerP = chart.PercentileWhiskers, 5 }},
+		{"Width ~ sqrt(n), 3*IQR", func(c *chart.BoxChart) { c.VarWidth, c.WhiskerK = true, 3 }},
+		{"Horizontal Notched Boxes", func(c *chart.BoxChart) { c.Horizontal, c.Notched = true, true }},
+	} {
+		p := chart.BoxChart{Title: opt.title}
+		opt.set(&p)
+		p.XRange.Label, p.YRange.Label = "Group", "Value"
+		p.XRange.Fixed(-1, 3, 1)
+		p.XRange.Category = []string{"A (n=160)", "B (n=45)", "C (n=400)"}
+		p.Key.Hide = true
+		p.NextDataSet("", style)
+		for i, data := range sets {
+			p.AddSet(float64(i), append([]float64(nil), data...), true)
+		}
+		dumper.Plot(&p)
+	}
+}
+
+func distributionChart() {
+	dumper := NewDumper("xecdf", 3, 1, 400, 350)
+	defer dumper.Close()
+
+	fast, slow := make([]float64, 200), make([]float64, 120)
+	for i := range fast {
+		fast[i] = 5 + 10*rand.ExpFloat64()
+	}
+	for i := range slow {
+		slow[i] = 25 + 6*rand.NormFloat64()
+	}
+
+	c := chart.ScatterChart{Title: "ECDF of Latency"}
+	c.XRange.Label, c.YRange.Label = "Latency [ms]", "Fraction <= Latency"
+	c.Key.Pos = "ibr"
+	c.AddECDF("Server A (95% band)", fast, 0.95, chart.Style{})
+	c.AddECDF("Server B (95% band)", slow, 0.95, chart.Style{})
+	dumper.Plot(&c)
+
+	qq := chart.ScatterChart{Title: "Q-Q Plot against Normal"}
+	qq.XRange.Label, qq.YRange.Label = "Normal Quantiles", "Sample Quantiles"
+	qq.Key.Pos = "itl"
+	qq.AddQQ("Server A", fast, chart.NormalQuantile(chart.Mean(fast), chart.StdDev(fast)), chart.Style{})
+	qq.AddQQ("Server B", slow, chart.NormalQuantile(chart.Mean(slow), chart.StdDev(slow)), chart.Style{})
+	dumper.Plot(&qq)
+
+	qq2 := chart.ScatterChart{Title: "Two-Sample Q-Q Plot"}
+	qq2.XRange.Label, qq2.YRange.Label = "Server A [ms]", "Server B [ms]"
+	qq2.Key.Hide = true
+	qq2.AddQQSamples("B vs. A", fast, slow, chart.Style{})
+	dumper.Plot(&qq2)
+}
+
+func digestChart() {
+	dumper := NewDumper("xdigest", 2, 1, 400, 300)
+	defer dumper.Close()
+
+	// One million samples per day are streamed into digests.
+	days := []string{"Mon", "Tue", "Wed"}
+	digests := make([]*chart.TDigest, len(days))
+	for i := range digests {
+		digests[i] = chart.NewTDigest(100)
+		for j := 0; j < 1000000; j++ {
+			digests[i].Add(20*float64(i+1)+5*rand.NormFloat64()+10*rand.ExpFloat64(), 1)
+		}
+	}
+
+	bc := chart.BoxChart{Title: "Boxes from Digests", Notched: true}
+	bc.XRange.Label, bc.YRange.Label = "Day", "Latency [ms]"
+	bc.XRange.Fixed(-1, 3, 1)
+	bc.XRange.Category = days
+	bc.Key.Hide = true
+	bc.NextDataSet("", chart.Style{Symbol: 'o', LineColor: color.NRGBA{0x00, 0x00, 0xcc, 0xff}, LineWidth: 1,
+		LineStyle: chart.SolidLine, FillColor: color.NRGBA{0xb0, 0xc0, 0xff, 0xff}})
+	for i, d := range digests {
+		bc.AddDigest(float64(i), d)
+	}
+	dumper.Plot(&bc)
+
+	hc := chart.HistChart{Title: "Histogram from Digest", Density: true, BinWidth: 5}
+	hc.XRange.Label, hc.YRange.Label = "Latency [ms]", "Density"
+	hc.Key.Hide = true
+	hc.AddDigest(days[0], digests[0], chart.Style{})
+	dumper.Plot(&hc)
+}
+
 // gaussian distribution with n samples, stddev of s, offset of a, forced to [l,u]
 func gauss(n int, s, a, l, u float64) []float64 {
 	// Make output of gauss deterministic by seeding with a fixed value.
@@ -560,6 +764,131 @@ func histChart(name, title string, stacked, counts, shifted bool) {
 	dumper.Plot(&hc)
 }
 
+// Cumulative, density and logarithmic histograms
+func histModesChart() {
+	dumper := NewDumper("xhistm", 3, 2, 400, 300)
+	defer dumper.Close()
+
+	points := gauss(300, 8, 25, 0, 50)
+
+	hc := chart.HistChart{Title: "Probability Density", Density: true, Kernel: chart.GaussKernel}
+	hc.XRange.Label, hc.YRange.Label = "Sample Value", "Density"
+	hc.Key.Hide = true
+	hc.AddData("Sample", points, chart.Style{})
+	dumper.Plot(&hc)
+
+	hc = chart.HistChart{Title: "Cumulative Ascending", Density: true, Cumulative: 1}
+	hc.XRange.Label, hc.YRange.Label = "Sample Value", "Fraction"
+	hc.Key.Hide = true
+	hc.AddData("Sample", points, chart.Style{})
+	dumper.Plot(&hc)
+
+	hc = chart.HistChart{Title: "Cumulative Descending", Counts: true, Cumulative: -1, Stacked: true}
+	hc.XRange.Label, hc.YRange.Label = "Sample Value", "Count"
+	hc.Key.Pos = "itr"
+	hc.AddData("Sample 1", points, chart.Style{})
+	hc.AddData("Sample 2", gauss(100, 4, 37, 0, 50), chart.Style{})
+	dumper.Plot(&hc)
+
+	rand.Seed(54321)
+	latency := make([]float64, 2000)
+	for i := range latency {
+		latency[i] = 10 * rand.ExpFloat64()
+	}
+	hc = chart.HistChart{Title: "Logarithmic Counts", Counts: true, BinWidth: 5}
+	hc.XRange.Label, hc.YRange.Label = "Latency [ms]", "Count"
+	hc.XRange.MinMode.Fixed, hc.XRange.MinMode.Value = true, 0
+	hc.YRange.Log = true
+	hc.Key.Hide = true
+	hc.AddData("Latency", latency, chart.Style{})
+	dumper.Plot(&hc)
+
+	// Pre-aggregated samples: Response times in ms and their counts.
+	aggregated := []chart.XYValue{}
+	for ms := 1.0; ms <= 60; ms++ {
+		n := 1e6 * math.Exp(-(ms-20)*(ms-20)/50) * (1 + ms/40)
+		aggregated = append(aggregated, chart.Point{X: ms, Y: math.Floor(n)})
+	}
+	hc = chart.HistChart{Title: "Weighted Samples", Counts: true, Binning: chart.SturgesBinning}
+	hc.XRange.Label, hc.YRange.Label = "Response Time [ms]", "Count"
+	hc.Key.Hide = true
+	hc.AddWeightedDataGeneric("Requests", aggregated, chart.Style{})
+	dumper.Plot(&hc)
+
+	hc = chart.HistChart{Title: "Weighted Density", Density: true, Kernel: chart.EpanechnikovKernel, BinWidth: 5}
+	hc.XRange.Label, hc.YRange.Label = "Response Time [ms]", "Density"
+	hc.Key.Hide = true
+	hc.AddWeightedDataGeneric("Requests", aggregated, chart.Style{})
+	dumper.Plot(&hc)
+}
+
+// Binning strategies for skewed data
+func histBinningChart() {
+	dumper := NewDumper("xhistb", 3, 2, 400, 300)
+	defer dumper.Close()
+
+	rand.Seed(2468)
+	income := make([]float64, 1000)
+	for i := range income {
+		income[i] = math.Exp(rand.NormFloat64()*0.6 + 3.5)
+	}
+
+	for _, b := range []struct {
+		title   string
+		binning chart.Binning
+		edges   []float64
+	}{
+		{"Sturges", chart.SturgesBinning, nil},
+		{"Scott", chart.ScottBinning, nil},
+		{"Freedman-Diaconis", chart.FreedmanDiaconisBinning, nil},
+		{"Doane", chart.DoaneBinning, nil},
+		{"Quantile (10 bins)", chart.QuantileBinning, nil},
+		{"Explicit Edges", 0, []float64{0, 10, 20, 30, 40, 60, 80, 120, 200, 300}},
+	} {
+		hc := chart.HistChart{Title: b.title, Density: true, Binning: b.binning, BinEdges: b.edges, BinCount: 10}
+		hc.XRange.Label, hc.YRange.Label = "Income [k$]", "Density"
+		hc.XRange.MinMode.Fixed, hc.XRange.MinMode.Value = true, 0
+		hc.Key.Hide = true
+		hc.AddData("Income", income, chart.Style{})
+		dumper.Plot(&hc)
+	}
+}
+
+// Kernel density estimates
+func kdeChart() {
+	dumper := NewDumper("xkde", 2, 1, 500, 350)
+	defer dumper.Close()
+
+	latency := make([]float64, 300)
+	for i := range latency {
+		latency[i] = 10 * rand.ExpFloat64()
+	}
+
+	sc := chart.ScatterChart{Title: "Density of Latency"}
+	sc.XRange.Label, sc.YRange.Label = "Latency [ms]", "Density"
+	sc.Key.Pos = "itr"
+	sc.AddData("Silverman", chart.DensityCurve(latency, 200), chart.PlotStyleLines, chart.Style{})
+	bounded := chart.KDE{Lower: 0, BoundedBelow: true}
+	sc.AddData("Silverman, x>=0", bounded.Curve(latency, nil, 0, 0, 200), chart.PlotStyleLines, chart.Style{})
+	bounded.Rule = chart.CrossValidationBandwidth
+	sc.AddData("Cross-Val., x>=0", bounded.Curve(latency, nil, 0, 0, 200), chart.PlotStyleLines, chart.Style{})
+	sc.AddFunc("Theory", func(x float64) float64 {
+		if x < 0 {
+			return 0
+		}
+		return math.Exp(-x/10) / 10
+	}, chart.PlotStyleLines, chart.Style{LineColor: color.NRGBA{0x60, 0x60, 0x60, 0xff}, LineWidth: 1, LineStyle: chart.DashedLine})
+	dumper.Plot(&sc)
+
+	hc := chart.HistChart{Title: "Histogram with Smoothed Density", Density: true, Binning: chart.ScottBinning,
+		Kernel: chart.EpanechnikovKernel}
+	hc.Smoothing = chart.KDE{Rule: chart.SilvermanBandwidth, Lower: 0, BoundedBelow: true}
+	hc.XRange.Label, hc.YRange.Label = "Latency [ms]", "Density"
+	hc.Key.Hide = true
+	hc.AddData("Latency", latency, chart.Style{})
+	dumper.Plot(&hc)
+}
+
 //
 // Bar Charts
 //
@@ -608,6 +937,28 @@ func barChart() {
 
 	barc.Stacked = true
 	dumper.Plot(&barc)
+
+	// Error bars
+	dumper2 := NewDumper("xbar5", 2, 1, 400, 300)
+	defer dumper2.Close()
+	means := func(y, lo, hi []float64) []chart.XYErrValue {
+		data := make([]chart.XYErrValue, len(y))
+		for i := range y {
+			data[i] = chart.EPoint{X: float64(i), Y: y[i], DeltaY: lo[i] + hi[i], OffY: (hi[i] - lo[i]) / 2}
+		}
+		return data
+	}
+	barc = chart.BarChart{Title: "Means with Error Bars"}
+	barc.Key.Pos, barc.Key.Cols = "obc", 2
+	barc.XRange.Category = []string{"A", "B", "C", "D"}
+	barc.AddDataGeneric("Control", means([]float64{12, 15, 9, -4}, []float64{2, 3, 1, 2}, []float64{2, 1, 3, 2}), red)
+	barc.AddDataGeneric("Treatment", means([]float64{14, 11, 13, -6}, []float64{1, 2, 2, 1}, []float64{3, 2, 2, 1}), green)
+	dumper2.Plot(&barc)
+
+	barc.Title = "Stacked with Error Bars"
+	barc.Stacked = true
+	barc.Key.Hide = true
+	dumper2.Plot(&barc)
 }
 
 //
@@ -707,6 +1058,83 @@ func categoricalBarChart() {
 	c.AddDataPair("America", x, []float64{15, -5, -10, -20}, red)
 	c.YRange.TicSetting.Delta = 0
 	dumper2.Plot(&c)
+
+	// Percent and diverging stacks
+	dumper3 := NewDumper("xbar4", 2, 2, 400, 300)
+	defer dumper3.Close()
+
+	c = chart.BarChart{Title: "Income (percent)", Stacked: true, Percent: true, ShowVal: 3}
+	c.XRange.Category = []string{"none", "low", "average", "high"}
+	c.Key.Pos = "orc"
+	c.AddDataPair("Europe", x, europe, blue)
+	c.AddDataPair("Asia", x, asia, pink)
+	c.AddDataPair("Africa", x, africa, green)
+	dumper3.Plot(&c)
+
+	c = chart.BarChart{Title: "Mixed Signs Stacked", Stacked: true, ShowVal: 3}
+	c.XRange.Category = []string{"none", "low", "average", "high"}
+	c.Key.Hide = true
+	c.AddDataPair("Europe", x, []float64{-10, 15, -20, 5}, blue)
+	c.AddDataPair("Asia", x, []float64{-15, 10, 5, 20}, pink)
+	c.AddDataPair("Africa", x, []float64{10, -10, 15, -5}, green)
+	dumper3.Plot(&c)
+
+	likert := []struct {
+		name  string
+		val   []float64
+		style chart.Style
+	}{
+		{"disagree", []float64{10, 25, 5, 30}, red},
+		{"rather not", []float64{15, 20, 10, 25}, pink},
+		{"neutral", []float64{20, 10, 15, 20}, chart.Style{Symbol: '.', LineColor: color.NRGBA{0x80, 0x80, 0x80, 0xff},
+			LineWidth: 1, FillColor: color.NRGBA{0xc0, 0xc0, 0xc0, 0xff}}},
+		{"rather yes", []float64{30, 25, 40, 15}, blue},
+		{"agree", []float64{25, 20, 30, 10}, green},
+	}
+	c = chart.BarChart{Title: "Likert (diverging)", Stacked: true, Diverging: true, Neutral: "neutral", Percent: true, ShowVal: 3}
+	c.XRange.Category = []string{"Q1", "Q2", "Q3", "Q4"}
+	c.Key.Pos = "orc"
+	c.YRange.TicSetting.Format = func(f float64) string { return fmt.Sprintf("%.0f%%", math.Abs(f)) }
+	for _, l := range likert {
+		c.AddDataPair(l.name, x, l.val, l.style)
+	}
+	dumper3.Plot(&c)
+
+	c = chart.BarChart{Title: "Diverging, no Neutral", Stacked: true, Diverging: true, ShowVal: 3}
+	c.XRange.Category = []string{"Q1", "Q2", "Q3", "Q4"}
+	c.Key.Pos = "orc"
+	for _, i := range []int{0, 1, 3, 4} {
+		c.AddDataPair(likert[i].name, x, likert[i].val, likert[i].style)
+	}
+	dumper3.Plot(&c)
+}
+
+func waterfallChart() {
+	dumper := NewDumper("xwaterfall", 1, 2, 700, 350)
+	defer dumper.Close()
+
+	c := chart.WaterfallChart{Title: "Profit Bridge", ShowVal: 1}
+	c.AddStart("2023", 420)
+	c.AddStep("Sales", 130)
+	c.AddStep("Prices", 45)
+	c.AddStep("Costs", -95)
+	c.AddTotal("Subtotal")
+	c.AddStep("Taxes", -60)
+	c.AddStep("FX", -15)
+	c.AddTotal("2024")
+	c.Key.Pos = "obc"
+	c.Key.Cols = 3
+	dumper.Plot(&c)
+
+	c = chart.WaterfallChart{Title: "Cash Flow", ShowVal: 3, BarWidthFac: 0.8}
+	c.FmtVal = chart.NumberFormat{Style: chart.CurrencyNumber, Currency: "$"}.Format
+	c.AddStep("Q1", 30)
+	c.AddStep("Q2", -50)
+	c.AddStep("Q3", -20)
+	c.AddStep("Q4", 25)
+	c.AddTotal("Year")
+	c.Key.Hide = true
+	dumper.Plot(&c)
 }
 
 //
@@ -783,9 +1211,9 @@ func pieChart() {
 	pie := chart.PieChart{Title: "Some Pies"}
 	data := []chart.CatValue{{"D", 10, false}, {"GB", 20, true}, {"CH", 30, false}, {"F", 60, false}}
 	lw := 4
-	red := chart.Style{LineColor: color.NRGBA{0xcc, 0x00, 0x00, 0xff}, FillColor: color.NRGBA{0xff, 0x80, 0x80, 0xff},
+	red := chart.Style{Symbol: '#', LineColor: color.NRGBA{0xcc, 0x00, 0x00, 0xff}, FillColor: color.NRGBA{0xff, 0x80, 0x80, 0xff},
 		LineStyle: chart.SolidLine, LineWidth: lw}
-	green := chart.Style{LineColor: color.NRGBA{0x00, 0xcc, 0x00, 0xff}, FillColor: color.NRGBA{0x80, 0xff, 0x80, 0xff},
+	green := chart.Style{Symbol: 'o', LineColor: color.NRGBA{0x00, 0xcc, 0x00, 0xff}, FillColor: color.NRGBA{0x80, 0xff, 0x80, 0xff},
 		LineStyle: chart.SolidLine, LineWidth: lw}
 	blue := chart.Style{LineColor: color.NRGBA{0x00, 0x00, 0xcc, 0xff}, LineWidth: lw, LineStyle: chart.SolidLine, FillColor: color.NRGBA{0x80, 0x80, 0xff, 0xff}}
 	pink := chart.Style{LineColor: color.NRGBA{0x99, 0x00, 0x99, 0xff}, LineWidth: lw, LineStyle: chart.SolidLine, FillColor: color.NRGBA{0xaa, 0x60, 0xaa, 0xff}}
@@ -822,6 +1250,97 @@ func pieChart() {
 	pie.AddData("1980", data, styles)
 	pie.AddData("2010", data2, lightstyles)
 	dumper2.Plot(&pie)
+
+	dumper3 := NewDumper("xpie3", 2, 2, 500, 400)
+	defer dumper3.Close()
+	pie = chart.PieChart{Title: "Outside Labels", CatLabels: true, LabelPos: chart.OutsideLabels}
+	pie.FmtVal = chart.NumberFormat{Style: chart.PercentNumber, Locale: chart.EnglishLocale}.Share
+	pie.AddDataPair("Browsers", []string{"Chrome", "Safari", "Edge", "Firefox", "Opera", "Samsung", "Other"},
+		[]float64{65, 18, 5, 3, 2, 2, 5})
+	pie.Key.Hide = true
+	dumper3.Plot(&pie)
+
+	pie.Title = "Auto Labels"
+	pie.LabelPos = chart.AutoLabels
+	pie.Inner = 0.5
+	dumper3.Plot(&pie)
+
+	langs := []string{"Go", "Rust", "C", "Java", "Python", "Perl", "Ruby", "Lua", "Tcl", "Awk", "Haskell", "OCaml"}
+	users := []float64{30, 12, 8, 25, 40, 2, 3, 1, 0.5, 0.5, 1.5, 1}
+	pie = chart.PieChart{Title: "Sorted, Small Merged", Sort: true, MinFraction: 0.03, CatLabels: true}
+	pie.Key.Pos = "orc"
+	pie.FmtKey = chart.NumberFormat{Style: chart.PercentNumber}.Share
+	pie.AddDataPair("Languages", langs, users)
+	dumper3.Plot(&pie)
+
+	pie = chart.PieChart{Title: "Top 5", TopN: 5, OtherName: "All others", LabelPos: chart.AutoLabels}
+	pie.Key.Pos = "orc"
+	pie.FmtVal = chart.IntegerValue
+	pie.AddDataPair("Languages", langs, users)
+	dumper3.Plot(&pie)
+}
+
+func sunburstChart() {
+	dumper := NewDumper("xsunburst", 2, 1, 500, 400)
+	defer dumper.Close()
+
+	sb := chart.SunburstChart{Title: "World Population (Mio)", CatLabels: true}
+	sb.Key.Pos = "orc"
+	for _, p := range []struct {
+		path []string
+		val  float64
+	}{
+		{[]string{"Asia", "India"}, 1430}, {[]string{"Asia", "China"}, 1410},
+		{[]string{"Asia", "Indonesia"}, 278}, {[]string{"Asia", "Pakistan"}, 240},
+		{[]string{"Africa", "Nigeria"}, 224}, {[]string{"Africa", "Ethiopia"}, 127},
+		{[]string{"Africa", "Egypt"}, 113},
+		{[]string{"Europe", "EU", "Germany"}, 84}, {[]string{"Europe", "EU", "France"}, 68},
+		{[]string{"Europe", "EU", "Italy"}, 59}, {[]string{"Europe", "Russia"}, 144},
+		{[]string{"Europe", "UK"}, 68},
+		{[]string{"America", "USA"}, 340}, {[]string{"America", "Brazil"}, 216},
+		{[]string{"America", "Mexico"}, 128},
+	} {
+		sb.AddPath(p.path, p.val)
+	}
+	dumper.Plot(&sb)
+
+	sb.Title = "With Hole and Values"
+	sb.Inner = 0.3
+	sb.CatLabels = false
+	sb.FmtVal = chart.IntegerValue
+	dumper.Plot(&sb)
+}
+
+func gaugeChart() {
+	dumper := NewDumper("xgauge", 2, 2, 400, 300)
+	defer dumper.Close()
+
+	pie := chart.PieChart{Title: "Ring with Center Label", Inner: 0.6, Center: "1,234\nusers"}
+	pie.Key.Hide = true
+	pie.AddDataPair("Users", []string{"Free", "Pro", "Team"}, []float64{800, 300, 134})
+	dumper.Plot(&pie)
+
+	red := chart.Style{Symbol: '#', LineColor: color.NRGBA{0xcc, 0x00, 0x00, 0xff}, LineWidth: 1, LineStyle: chart.SolidLine,
+		FillColor: color.NRGBA{0xff, 0x40, 0x40, 0xff}}
+	yellow := chart.Style{Symbol: '+', LineColor: color.NRGBA{0xcc, 0xaa, 0x00, 0xff}, LineWidth: 1, LineStyle: chart.SolidLine,
+		FillColor: color.NRGBA{0xff, 0xdd, 0x40, 0xff}}
+	green := chart.Style{Symbol: 'o', LineColor: color.NRGBA{0x00, 0xaa, 0x00, 0xff}, LineWidth: 1, LineStyle: chart.SolidLine,
+		FillColor: color.NRGBA{0x40, 0xdd, 0x40, 0xff}}
+
+	gauge := chart.GaugeChart{Title: "CPU Load", Min: 0, Max: 100, Value: 73, Label: "percent"}
+	gauge.AddBand(0, 60, "ok", green)
+	gauge.AddBand(60, 85, "warning", yellow)
+	gauge.AddBand(85, 100, "critical", red)
+	gauge.Key.Hide = true
+	dumper.Plot(&gauge)
+
+	gauge.Title, gauge.Needle, gauge.Value = "Needle", true, 91
+	dumper.Plot(&gauge)
+
+	gauge = chart.GaugeChart{Title: "270° Gauge", Min: 0, Max: 8000, Value: 3500, Angle: 270, Label: "rpm"}
+	gauge.AddBand(6500, 8000, "red line", red)
+	gauge.Key.Pos = "itr"
+	dumper.Plot(&gauge)
 }
 
 func textlen() {
@@ -1292,6 +1811,83 @@ func bestOf() {
 	}
 }
 
+func rollingChart() {
+	dumper := NewDumper("xrolling", 1, 2, 600, 300)
+	defer dumper.Close()
+
+	// Hourly measurements with noise, some spikes and a few missing hours.
+	t0 := time.Date(2012, 6, 1, 0, 0, 0, 0, time.UTC)
+	var raw []chart.EPoint
+	for h := 0; h < 24*21; h++ {
+		if h%97 < 5 {
+			continue
+		}
+		y := 20 + 0.02*float64(h) + 3*math.Sin(float64(h)*2*math.Pi/24) + 2*rand.NormFloat64()
+		if rand.Intn(40) == 0 {
+			y += 15
+		}
+		x := float64(t0.Add(time.Duration(h) * time.Hour).Unix())
+		raw = append(raw, chart.EPoint{X: x, Y: y, DeltaX: math.NaN(), DeltaY: math.NaN()})
+	}
+	gray := chart.Style{Symbol: '.', SymbolColor: color.NRGBA{0xa0, 0xa0, 0xa0, 0xff}}
+
+	c := chart.ScatterChart{Title: "Rolling Mean and Median"}
+	c.XRange.Time = true
+	c.XRange.Label, c.YRange.Label = "Date", "Temperature"
+	c.Key.Pos = "itl"
+	c.AddData("Hourly", raw, chart.PlotStylePoints, gray)
+	c.AddRolling("Mean 1 day", 0, chart.Rolling{Duration: chart.Day{1}, Centered: true},
+		chart.Style{Symbol: 'm', LineColor: color.NRGBA{0x00, 0x00, 0xcc, 0xff}, LineWidth: 2, LineStyle: chart.SolidLine})
+	c.AddRolling("Median 1 day", 0, chart.Rolling{Stat: chart.RollingMedian, Duration: chart.Day{1}, Centered: true},
+		chart.Style{Symbol: 'M', LineColor: color.NRGBA{0xcc, 0x00, 0x00, 0xff}, LineWidth: 2, LineStyle: chart.SolidLine})
+	dumper.Plot(&c)
+
+	c = chart.ScatterChart{Title: "Percentiles and Exponential Smoothing"}
+	c.XRange.Time = true
+	c.XRange.Label, c.YRange.Label = "Date", "Temperature"
+	c.Key.Pos = "itl"
+	c.AddData("Hourly", raw, chart.PlotStylePoints, gray)
+	green := chart.Style{Symbol: '=', LineColor: color.NRGBA{0x00, 0x90, 0x00, 0xff}, LineWidth: 1, LineStyle: chart.DashedLine}
+	c.AddRolling("10-90% (48 samples)", 0, chart.Rolling{Stat: chart.RollingPercentile, P: 10, Count: 48}, green)
+	c.AddRolling("", 0, chart.Rolling{Stat: chart.RollingPercentile, P: 90, Count: 48}, green)
+	c.AddRolling("Exp. smoothing 12h", 0, chart.Rolling{Stat: chart.ExponentialSmoothing, Duration: chart.Hour{12}},
+		chart.Style{Symbol: 'e', LineColor: color.NRGBA{0xb0, 0x00, 0xb0, 0xff}, LineWidth: 2, LineStyle: chart.SolidLine})
+	dumper.Plot(&c)
+}
+
+func gapChart() {
+	dumper := NewDumper("xgap", 1, 2, 600, 250)
+	defer dumper.Close()
+
+	// Metric sampled every 10 minutes with a missing value (NaN), one
+	// outage of three hours and samples missing completely.
+	t0 := time.Date(2012, 6, 1, 0, 0, 0, 0, time.UTC)
+	var data []chart.EPoint
+	for m := 0; m < 24*60; m += 10 {
+		if m >= 14*60 && m < 17*60 {
+			continue // outage
+		}
+		y := 50 + 20*math.Sin(float64(m)/1440*2*math.Pi) + 3*rand.NormFloat64()
+		if m == 6*60 {
+			y = math.NaN() // missing value
+		}
+		x := float64(t0.Add(time.Duration(m) * time.Minute).Unix())
+		data = append(data, chart.EPoint{X: x, Y: y, DeltaX: math.NaN(), DeltaY: math.NaN()})
+	}
+	style := chart.Style{Symbol: '*', LineColor: color.NRGBA{0x00, 0x60, 0xc0, 0xff}, LineWidth: 2, LineStyle: chart.SolidLine}
+	for _, gap := range []chart.TimeDelta{nil, chart.Minute{30}} {
+		c := chart.ScatterChart{Title: "NaN breaks the line", GapDelta: gap}
+		if gap != nil {
+			c.Title = "Gaps longer than 30 minutes break the line"
+		}
+		c.XRange.Time = true
+		c.XRange.Label, c.YRange.Label = "Time", "Load"
+		c.Key.Hide = true
+		c.AddData("Load", data, chart.PlotStyleLines, style)
+		dumper.Plot(&c)
+	}
+}
+
 func timeRange() {
 	factors := []int64{1, 2, 3, 5, 7, 9, 11, 15}
 	magnitudes := []int64{1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9}
@@ -1514,6 +2110,9 @@ func main() {
 	var box *bool = flag.Bool("box", false, "show box charts")
 	var strip *bool = flag.Bool("strip", false, "show strip charts")
 	var pie *bool = flag.Bool("pie", false, "show pie charts")
+	var sunburst *bool = flag.Bool("sunburst", false, "show sunburst charts")
+	var gauge *bool = flag.Bool("gauge", false, "show gauge charts and ring charts with center label")
+	var waterfall *bool = flag.Bool("waterfall", false, "show waterfall charts")
 	var scatter *bool = flag.Bool("scatter", false, "show scatter charts")
 	var hist *bool = flag.Bool("hist", false, "show hist charts")
 	var shist *bool = flag.Bool("shist", false, "show stacked hist charts")
@@ -1526,9 +2125,11 @@ func main() {
 	var auto *bool = flag.Bool("auto", false, "show autoscaling")
 	var key *bool = flag.Bool("key", false, "show key placement")
 	var funcs *bool = flag.Bool("func", false, "show function plots")
+	var annot *bool = flag.Bool("annot", false, "show annotations")
 	var best *bool = flag.Bool("best", false, "show best of plots")
 	var zeit *bool = flag.Bool("time", false, "show time plots")
 	var test *bool = flag.Bool("test", false, "produce graphic test")
+	flag.BoolVar(&Braille, "braille", false, "use braille patterns and block elements in text output")
 
 	flag.Parse()
 	if *debugging {
@@ -1549,8 +2150,13 @@ func main() {
 	if *all || *bar {
 		barChart()
 	}
+	if *all || *waterfall {
+		waterfallChart()
+	}
 	if *all || *box {
 		boxChart()
+		boxOptionsChart()
+		digestChart()
 	}
 
 	if *all || *strip {
@@ -1560,13 +2166,25 @@ func main() {
 	if *all || *pie {
 		pieChart()
 	}
+	if *all || *sunburst {
+		sunburstChart()
+	}
+	if *all || *gauge {
+		gaugeChart()
+	}
 
 	if *all || *scatter {
 		scatterChart()
+		fitChart()
+		decimationChart()
 	}
 	if *all || *hist {
 		histChart("xhistn1.svg", "Normal Histogram", false, false, false)
 		histChart("xhistn2.svg", "Normal Histogram", false, true, false)
+		histModesChart()
+		histBinningChart()
+		kdeChart()
+		distributionChart()
 	}
 	if *all || *shist {
 		histChart("xhists1.svg", "Stacked Histogram", true, false, false)
@@ -1596,6 +2214,9 @@ func main() {
 	if *special || *funcs {
 		functionPlots()
 	}
+	if *special || *annot {
+		annotations()
+	}
 	if *special || *test {
 		testGraphics()
 	}
@@ -1606,6 +2227,8 @@ func main() {
 
 	if *zeit {
 		timeRange()
+		rollingChart()
+		gapChart()
 	}
 
 	/*
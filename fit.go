@@ -0,0 +1,393 @@
+package chart
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// FitModel selects the model of a Fit.
+type FitModel int
+
+const (
+	LinearFit      FitModel = iota // y = a + b*x
+	PolynomialFit                  // y = c0 + c1*x + ... + cd*x^d
+	ExponentialFit                 // y = a*exp(b*x), least squares fit of log(y), needs y > 0
+	PowerFit                       // y = a*x^b, least squares fit of log(y) on log(x), needs x, y > 0
+	LoessFit                       // locally weighted linear regression (LOWESS without robustness iterations)
+)
+
+// Fit describes a regression of y on x. Samples with NaN values or outside
+// the domain of the model are ignored.
+type Fit struct {
+	Model      FitModel
+	Degree     int     // degree of PolynomialFit, 0 is 2
+	Span       float64 // fraction of samples in each local regression of LoessFit, 0 is 0.75
+	Confidence float64 // level of the confidence band of the mean of a LinearFit (e.g. 0.95), 0: no band
+	Equation   bool    // append the fitted equation and R² to the key entry (see ScatterChart.AddFit)
+}
+
+// FitResult is a fitted model.
+type FitResult struct {
+	Fit
+	Coeffs []float64 // a and b for LinearFit, ExponentialFit and PowerFit, c0...cd for PolynomialFit, nil for LoessFit
+	R2     float64   // coefficient of determination 1 - SSres/SStot in units of y
+	N      int       // number of samples used
+
+	// The polynomial part of the model is evaluated in the scaled
+	// variable (u-um)/us to avoid loss of precision, e.g. on time axes.
+	poly   []float64
+	um, us float64
+
+	sx, sy []float64 // samples sorted by x for LoessFit
+
+	// Confidence band of LinearFit: t*s*sqrt(1/n + (x-xm)^2/sxx).
+	ts, xm, sxx float64
+}
+
+// Compute fits the model to the samples (x[i],y[i]).
+func (f Fit) Compute(x, y []float64) *FitResult {
+	r := &FitResult{Fit: f, R2: math.NaN(), ts: math.NaN()}
+	var u, v []float64 // the transformed samples
+	for i := 0; i < len(x) && i < len(y); i++ {
+		xi, yi := x[i], y[i]
+		if math.IsNaN(xi) || math.IsNaN(yi) {
+			continue
+		}
+		switch f.Model {
+		case ExponentialFit:
+			if yi <= 0 {
+				continue
+			}
+			yi = math.Log(yi)
+		case PowerFit:
+			if xi <= 0 || yi <= 0 {
+				continue
+			}
+			xi, yi = math.Log(xi), math.Log(yi)
+		}
+		u, v = append(u, xi), append(v, yi)
+	}
+	r.N = len(u)
+
+	switch f.Model {
+	case LoessFit:
+		r.sx, r.sy = u, v
+		sort.Sort(weightedSamples{r.sx, r.sy})
+	case PolynomialFit:
+		degree := f.Degree
+		if degree <= 0 {
+			degree = 2
+		}
+		r.fitPoly(u, v, degree)
+		r.Coeffs = r.rawPoly()
+	default:
+		r.fitPoly(u, v, 1)
+		r.Coeffs = r.rawPoly()
+		if f.Model == ExponentialFit || f.Model == PowerFit {
+			r.Coeffs[0] = math.Exp(r.Coeffs[0])
+		}
+	}
+
+	// R² in units of y.
+	if r.N > 1 {
+		xv, yv := make([]float64, r.N), make([]float64, r.N)
+		for i := range u {
+			xv[i], yv[i] = u[i], v[i]
+			switch f.Model {
+			case ExponentialFit:
+				yv[i] = math.Exp(v[i])
+			case PowerFit:
+				xv[i], yv[i] = math.Exp(u[i]), math.Exp(v[i])
+			}
+		}
+		var ssres, sstot float64
+		ym := sum(yv) / float64(r.N)
+		for i, yi := range yv {
+			d := yi - r.Eval(xv[i])
+			ssres += d * d
+			sstot += (yi - ym) * (yi - ym)
+		}
+		r.R2 = 1 - ssres/sstot
+
+		if f.Model == LinearFit && f.Confidence > 0 && f.Confidence < 1 && r.N > 2 {
+			r.xm = sum(u) / float64(r.N)
+			for _, xi := range u {
+				r.sxx += (xi - r.xm) * (xi - r.xm)
+			}
+			s := math.Sqrt(ssres / float64(r.N-2))
+			r.ts = studentTQuantile((1+f.Confidence)/2, float64(r.N-2)) * s
+		}
+	}
+	return r
+}
+
+// fitPoly determines the least squares polynomial of degree through the
+// samples (u[i],v[i]) by solving the normal equations.
+func (r *FitResult) fitPoly(u, v []float64, degree int) {
+	nan := math.NaN()
+	r.poly = make([]float64, degree+1)
+	for i := range r.poly {
+		r.poly[i] = nan
+	}
+	n := len(u)
+	if n <= degree {
+		return
+	}
+	r.um = sum(u) / float64(n)
+	for _, x := range u {
+		r.us = math.Max(r.us, math.Abs(x-r.um))
+	}
+	if r.us == 0 {
+		return
+	}
+
+	// Augmented matrix of the normal equations.
+	m := degree + 1
+	a := make([][]float64, m)
+	for i := range a {
+		a[i] = make([]float64, m+1)
+	}
+	pow := make([]float64, 2*m)
+	for k, x := range u {
+		t := (x - r.um) / r.us
+		pow[0] = 1
+		for j := 1; j < len(pow); j++ {
+			pow[j] = pow[j-1] * t
+		}
+		for i := 0; i < m; i++ {
+			for j := 0; j < m; j++ {
+				a[i][j] += pow[i+j]
+			}
+			a[i][m] += pow[i] * v[k]
+		}
+	}
+
+	// Gaussian elimination with partial pivoting.
+	for col := 0; col < m; col++ {
+		p := col
+		for i := col + 1; i < m; i++ {
+			if math.Abs(a[i][col]) > math.Abs(a[p][col]) {
+				p = i
+			}
+		}
+		if math.Abs(a[p][col]) < 1e-12*float64(n) {
+			return // singular
+		}
+		a[col], a[p] = a[p], a[col]
+		for i := col + 1; i < m; i++ {
+			f := a[i][col] / a[col][col]
+			for j := col; j <= m; j++ {
+				a[i][j] -= f * a[col][j]
+			}
+		}
+	}
+	for i := m - 1; i >= 0; i-- {
+		s := a[i][m]
+		for j := i + 1; j < m; j++ {
+			s -= a[i][j] * r.poly[j]
+		}
+		r.poly[i] = s / a[i][i]
+	}
+}
+
+// rawPoly expands the scaled polynomial to coefficients of powers of u.
+func (r *FitResult) rawPoly() []float64 {
+	m := len(r.poly)
+	raw := make([]float64, m)
+	for k, c := range r.poly {
+		// c * ((u-um)/us)^k = c/us^k * sum_j binom(k,j) u^j (-um)^(k-j)
+		binom := 1.0
+		for j := 0; j <= k; j++ {
+			raw[j] += c / math.Pow(r.us, float64(k)) * binom * math.Pow(-r.um, float64(k-j))
+			binom = binom * float64(k-j) / float64(j+1)
+		}
+	}
+	return raw
+}
+
+// polyEval evaluates the scaled polynomial at u.
+func (r *FitResult) polyEval(u float64) float64 {
+	t := (u - r.um) / r.us
+	y := 0.0
+	for i := len(r.poly) - 1; i >= 0; i-- {
+		y = y*t + r.poly[i]
+	}
+	return y
+}
+
+// Eval returns the value of the fitted model at x.
+func (r *FitResult) Eval(x float64) float64 {
+	switch r.Model {
+	case ExponentialFit:
+		return math.Exp(r.polyEval(x))
+	case PowerFit:
+		if x <= 0 {
+			return math.NaN()
+		}
+		return math.Exp(r.polyEval(math.Log(x)))
+	case LoessFit:
+		return r.loess(x)
+	}
+	return r.polyEval(x)
+}
+
+// Band returns the limits of the confidence band of the mean at x. Both are
+// NaN if no band was requested or the model is not LinearFit.
+func (r *FitResult) Band(x float64) (lo, hi float64) {
+	if math.IsNaN(r.ts) || r.sxx == 0 {
+		return math.NaN(), math.NaN()
+	}
+	y := r.Eval(x)
+	d := r.ts * math.Sqrt(1/float64(r.N)+(x-r.xm)*(x-r.xm)/r.sxx)
+	return y - d, y + d
+}
+
+// loess evaluates a local linear regression at x: The span*n nearest
+// samples are weighted by the tricube function of their distance.
+func (r *FitResult) loess(x float64) float64 {
+	n := len(r.sx)
+	if n == 0 {
+		return math.NaN()
+	}
+	span := r.Span
+	if span <= 0 {
+		span = 0.75
+	}
+	q := imin(n, imax(3, int(span*float64(n)+0.5)))
+
+	// Grow the window [lo,hi) of the q nearest samples around x.
+	lo := sort.SearchFloat64s(r.sx, x)
+	hi := lo
+	for hi-lo < q {
+		if lo > 0 && (hi == n || x-r.sx[lo-1] <= r.sx[hi]-x) {
+			lo--
+		} else {
+			hi++
+		}
+	}
+	h := math.Max(x-r.sx[lo], r.sx[hi-1]-x) * 1.0001 // all samples get positive weight
+	if h == 0 {
+		return sum(r.sy[lo:hi]) / float64(q)
+	}
+
+	var sw, swx, swy, swxx, swxy float64
+	for i := lo; i < hi; i++ {
+		d := math.Abs(r.sx[i]-x) / h
+		w := 1 - d*d*d
+		w = w * w * w
+		dx := r.sx[i] - x
+		sw += w
+		swx += w * dx
+		swy += w * r.sy[i]
+		swxx += w * dx * dx
+		swxy += w * dx * r.sy[i]
+	}
+	den := sw*swxx - swx*swx
+	if den <= 1e-12*sw*swxx {
+		return swy / sw
+	}
+	return (swxx*swy - swx*swxy) / den // intercept of regression in x-centered coordinates
+}
+
+// Equation returns the fitted model and R² as text, e.g. "y = 1.2 + 0.35x, R² = 0.97".
+func (r *FitResult) Equation() string {
+	num := func(v float64) string { return fmt.Sprintf("%.3g", v) }
+	var eq string
+	switch r.Model {
+	case ExponentialFit:
+		eq = fmt.Sprintf("y = %s·exp(%sx)", num(r.Coeffs[0]), num(r.Coeffs[1]))
+	case PowerFit:
+		eq = fmt.Sprintf("y = %s·x^%s", num(r.Coeffs[0]), num(r.Coeffs[1]))
+	case LoessFit:
+		span := r.Span
+		if span <= 0 {
+			span = 0.75
+		}
+		eq = fmt.Sprintf("LOESS span %s", num(span))
+	default:
+		var b strings.Builder
+		b.WriteString("y = ")
+		first := true
+		for k, c := range r.Coeffs {
+			if c == 0 && len(r.Coeffs) > 1 {
+				continue
+			}
+			s := num(math.Abs(c))
+			switch {
+			case first && c < 0:
+				b.WriteString("-")
+			case !first && c < 0:
+				b.WriteString(" - ")
+			case !first:
+				b.WriteString(" + ")
+			}
+			b.WriteString(s)
+			if k >= 1 {
+				b.WriteString("x")
+			}
+			if k > 1 {
+				fmt.Fprintf(&b, "^%d", k)
+			}
+			first = false
+		}
+		eq = b.String()
+	}
+	return eq + ", R² = " + fmt.Sprintf("%.3f", r.R2)
+}
+
+// degenerate reports whether too few (valid) samples or e.g. constant x
+// values leave the model undetermined.
+func (r *FitResult) degenerate() bool {
+	degree := 1
+	if r.Model == PolynomialFit {
+		degree = r.Degree
+		if degree <= 0 {
+			degree = 2
+		}
+	}
+	if r.N <= degree {
+		return true
+	}
+	for _, c := range r.Coeffs {
+		if math.IsNaN(c) || math.IsInf(c, 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddFit fits the model fit to the samples of data set i and adds the
+// fitted curve as function (and the confidence band as two dotted lines).
+// The key entry is name, followed by the equation if fit.Equation is set and
+// the samples determine the model.
+// An empty style draws a solid line in the color of data set i. AddFit must
+// be called after the data set has been added and does not autoscale: The
+// fit is drawn over the range of the axes.
+func (c *ScatterChart) AddFit(name string, i int, fit Fit, style Style) *FitResult {
+	data := c.Data[i]
+	x, y := make([]float64, len(data.Samples)), make([]float64, len(data.Samples))
+	for j, p := range data.Samples {
+		x[j], y[j] = p.X, p.Y
+	}
+	r := fit.Compute(x, y)
+
+	if style.empty() {
+		style = c.derivedStyle(i)
+	}
+	if fit.Equation && !r.degenerate() {
+		if name != "" {
+			name += ": "
+		}
+		name += r.Equation()
+	}
+	c.AddFunc(name, r.Eval, PlotStyleLines, style)
+	if !math.IsNaN(r.ts) {
+		band := style
+		band.Symbol, band.LineStyle = '.', DottedLine
+		c.AddFunc("", func(x float64) float64 { lo, _ := r.Band(x); return lo }, PlotStyleLines, band)
+		c.AddFunc("", func(x float64) float64 { _, hi := r.Band(x); return hi }, PlotStyleLines, band)
+	}
+	return r
+}
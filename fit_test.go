@@ -0,0 +1,89 @@
+package chart
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFitModels(t *testing.T) {
+	var x []float64
+	for i := 1; i <= 20; i++ {
+		x = append(x, float64(i)/2)
+	}
+	for _, tc := range []struct {
+		fit    Fit
+		f      func(float64) float64
+		coeffs []float64
+	}{
+		{Fit{Model: LinearFit}, func(x float64) float64 { return 2 + 3*x }, []float64{2, 3}},
+		{Fit{Model: PolynomialFit}, func(x float64) float64 { return 1 - x + 0.5*x*x }, []float64{1, -1, 0.5}},
+		{Fit{Model: PolynomialFit, Degree: 3}, func(x float64) float64 { return x * x * x }, []float64{0, 0, 0, 1}},
+		{Fit{Model: ExponentialFit}, func(x float64) float64 { return 2 * math.Exp(0.5*x) }, []float64{2, 0.5}},
+		{Fit{Model: PowerFit}, func(x float64) float64 { return 3 * math.Pow(x, 1.5) }, []float64{3, 1.5}},
+		{Fit{Model: LoessFit, Span: 0.3}, func(x float64) float64 { return 4 - x }, nil},
+	} {
+		y := make([]float64, len(x))
+		for i := range x {
+			y[i] = tc.f(x[i])
+		}
+		r := tc.fit.Compute(x, y)
+		if len(r.Coeffs) != len(tc.coeffs) || r.N != len(x) {
+			t.Fatalf("Model %d: Got %v (n=%d), expected %v", tc.fit.Model, r.Coeffs, r.N, tc.coeffs)
+		}
+		for i, c := range tc.coeffs {
+			if math.Abs(r.Coeffs[i]-c) > 1e-6 {
+				t.Errorf("Model %d: Got %v, expected %v", tc.fit.Model, r.Coeffs, tc.coeffs)
+				break
+			}
+		}
+		if math.Abs(r.R2-1) > 1e-9 {
+			t.Errorf("Model %d: R2=%g", tc.fit.Model, r.R2)
+		}
+		for _, xe := range []float64{0.75, 3.3, 9.9} {
+			if got, want := r.Eval(xe), tc.f(xe); math.Abs(got-want) > 1e-6*math.Max(1, math.Abs(want)) {
+				t.Errorf("Model %d: Eval(%g)=%g, expected %g", tc.fit.Model, xe, got, want)
+			}
+		}
+	}
+}
+
+func TestFitBand(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5, 6}
+	y := []float64{1.1, 1.9, 3.2, 3.9, 5.1, 5.8}
+	r := Fit{Confidence: 0.95}.Compute(x, y)
+	lo, hi := r.Band(3.5)
+	mid := r.Eval(3.5)
+	if !(lo < mid && mid < hi) {
+		t.Fatalf("Got %g < %g < %g", lo, mid, hi)
+	}
+	if lo1, hi1 := r.Band(1); hi1-lo1 <= hi-lo {
+		t.Errorf("Band not wider at the end: %g <= %g", hi1-lo1, hi-lo)
+	}
+	if lo, _ := (Fit{}).Compute(x, y).Band(3); !math.IsNaN(lo) {
+		t.Errorf("Unexpected band %g", lo)
+	}
+
+	if got := (Fit{}).Compute([]float64{0, 1, 2}, []float64{1, -1, -3}).Equation(); got != "y = 1 - 2x, R² = 1.000" {
+		t.Errorf("Got equation %q", got)
+	}
+}
+
+func TestFitEquationDegenerate(t *testing.T) {
+	for i, tc := range []struct {
+		x, y     []float64
+		fit      Fit
+		expected string
+	}{
+		{[]float64{1, 2, 3}, []float64{1, 3, 5}, Fit{Equation: true}, "fit: y = -1 + 2x, R² = 1.000"},
+		{[]float64{2, 2, 2}, []float64{1, 3, 5}, Fit{Equation: true}, "fit"},
+		{[]float64{1, 2}, []float64{1, 3}, Fit{Model: PolynomialFit, Equation: true}, "fit"},
+		{[]float64{1, 2, 3}, []float64{-1, 0, -5}, Fit{Model: ExponentialFit, Equation: true}, "fit"},
+	} {
+		c := ScatterChart{}
+		c.AddDataPair("data", tc.x, tc.y, PlotStylePoints, Style{})
+		c.AddFit("fit", 0, tc.fit, Style{})
+		if got := c.Data[1].Name; got != tc.expected {
+			t.Errorf("%d: got key entry %q, expected %q", i, got, tc.expected)
+		}
+	}
+}
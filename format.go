@@ -0,0 +1,180 @@
+package chart
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Locale describes how numbers are written in a certain language or region.
+type Locale struct {
+	Decimal  string // decimal separator, "" is "."
+	Grouping string // separator between groups of thousands, "" means no grouping
+	Space    string // put between number and SI prefix, percent sign or currency
+}
+
+// Some common locales.
+var (
+	EnglishLocale = Locale{Decimal: ".", Grouping: ",", Space: " "}
+	GermanLocale  = Locale{Decimal: ",", Grouping: ".", Space: " "}
+	SwissLocale   = Locale{Decimal: ".", Grouping: "'", Space: " "}
+	FrenchLocale  = Locale{Decimal: ",", Grouping: " ", Space: " "}
+)
+
+// NumberStyle selects the basic representation of a number.
+type NumberStyle int
+
+const (
+	AutoNumber        NumberStyle = iota // like FmtFloat: few digits and SI prefixes for large and small numbers
+	FixedNumber                          // fixed number of decimals
+	ScientificNumber                     // mantissa and exponent like 1.23e4
+	EngineeringNumber                    // like ScientificNumber but exponent is multiple of 3: 12.3e3
+	PercentNumber                        // 100 times the value with percent sign: 0.123 --> 12.3 %
+	CurrencyNumber                       // fixed number of decimals and currency symbol
+)
+
+// SIPrefixes are the SI prefixes for 10^3n from 10^-24 to 10^24 as used by
+// NumberFormat. The prefix for 10^0 is "".
+var SIPrefixes = []string{"y", "z", "a", "f", "p", "n", "µ", "m", "", "k", "M", "G", "T", "P", "E", "Z", "Y"}
+
+// NumberFormat describes how to format numbers. The zero value formats like
+// FmtFloat but without space between number and SI prefix. The Format method
+// may be used directly as TicSetting.Format or BarChart.FmtVal and the Value
+// and Share methods as PieChart.FmtVal or PieChart.FmtKey.
+type NumberFormat struct {
+	Style         NumberStyle
+	Precision     int      // number of decimals, ignored for AutoNumber
+	Locale        Locale   // separators to use
+	Currency      string   // currency symbol for CurrencyNumber
+	CurrencyAfter bool     // put currency symbol behind the number as in "12,50 €"
+	Prefixes      []string // SI prefixes used in AutoNumber, nil means SIPrefixes
+}
+
+// Format returns the representation of f according to nf.
+func (nf NumberFormat) Format(f float64) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	switch nf.Style {
+	case FixedNumber:
+		return nf.Locale.number(strconv.FormatFloat(f, 'f', nf.Precision, 64))
+	case ScientificNumber:
+		return nf.exponential(f, 1)
+	case EngineeringNumber:
+		return nf.exponential(f, 3)
+	case PercentNumber:
+		return nf.Locale.number(strconv.FormatFloat(100*f, 'f', nf.Precision, 64)) + nf.Locale.Space + "%"
+	case CurrencyNumber:
+		s := nf.Locale.number(strconv.FormatFloat(f, 'f', nf.Precision, 64))
+		if nf.Currency == "" {
+			return s
+		}
+		if nf.CurrencyAfter {
+			return s + nf.Locale.Space + nf.Currency
+		}
+		if strings.HasPrefix(s, "-") {
+			return "-" + nf.Currency + nf.Locale.Space + s[1:]
+		}
+		return nf.Currency + nf.Locale.Space + s
+	}
+	return nf.auto(f)
+}
+
+// Value formats value and ignores sum. It can be assigned to PieChart.FmtVal
+// or PieChart.FmtKey.
+func (nf NumberFormat) Value(value, sum float64) string {
+	return nf.Format(value)
+}
+
+// Share formats value/sum, e.g. as a percentage if nf.Style is PercentNumber.
+// It can be assigned to PieChart.FmtVal or PieChart.FmtKey.
+func (nf NumberFormat) Share(value, sum float64) string {
+	return nf.Format(value / sum)
+}
+
+// auto formats f like FmtFloat does.
+func (nf NumberFormat) auto(f float64) string {
+	prefixes := nf.Prefixes
+	if prefixes == nil {
+		prefixes = SIPrefixes
+	}
+	af := math.Abs(f)
+	if f == 0 {
+		return "0"
+	} else if 1 <= af && af < 10 {
+		return nf.Locale.number(strconv.FormatFloat(f, 'f', 1, 64))
+	} else if 10 <= af && af <= 1000 {
+		return nf.Locale.number(strconv.FormatFloat(f, 'f', 0, 64))
+	}
+
+	// Scale f to [1,1000] and find matching prefix.
+	p := len(prefixes) / 2
+	for math.Abs(f) < 1 && p > 0 {
+		f *= 1000
+		p--
+	}
+	for math.Abs(f) > 1000 && p < len(prefixes)-1 {
+		f /= 1000
+		p++
+	}
+	af = math.Abs(f)
+	if af < 1 || af > 1000 {
+		// Out of prefix range.
+		return nf.exponential(f*math.Pow(1000, float64(p-len(prefixes)/2)), 1)
+	}
+	s := nf.auto(f)
+	if prefixes[p] != "" {
+		s += nf.Locale.Space + prefixes[p]
+	}
+	return s
+}
+
+// exponential formats f in scientific notation with exponents being multiples of m.
+func (nf NumberFormat) exponential(f float64, m int) string {
+	if f == 0 {
+		return nf.Locale.number(strconv.FormatFloat(0, 'f', nf.Precision, 64))
+	}
+	e := int(math.Floor(math.Log10(math.Abs(f))))
+	e -= ((e % m) + m) % m
+	mant := f / math.Pow10(e)
+	s := strconv.FormatFloat(mant, 'f', nf.Precision, 64)
+	// Rounding might yield a mantissa of 10^m.
+	if r, _ := strconv.ParseFloat(s, 64); math.Abs(r) >= math.Pow10(m) {
+		e += m
+		s = strconv.FormatFloat(f/math.Pow10(e), 'f', nf.Precision, 64)
+	}
+	return nf.Locale.number(s) + "e" + strconv.Itoa(e)
+}
+
+// number localizes the number s as produced by strconv.FormatFloat in 'f' format.
+func (l Locale) number(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	ip, fp := s, ""
+	if i := strings.Index(s, "."); i != -1 {
+		ip, fp = s[:i], s[i+1:]
+	}
+
+	if l.Grouping != "" && len(ip) > 3 {
+		groups := make([]string, 0, len(ip)/3+1)
+		first := len(ip) % 3
+		if first > 0 {
+			groups = append(groups, ip[:first])
+		}
+		for i := first; i < len(ip); i += 3 {
+			groups = append(groups, ip[i:i+3])
+		}
+		ip = strings.Join(groups, l.Grouping)
+	}
+
+	if fp == "" {
+		return sign + ip
+	}
+	dec := l.Decimal
+	if dec == "" {
+		dec = "."
+	}
+	return sign + ip + dec + fp
+}
@@ -0,0 +1,47 @@
+package chart
+
+import (
+	"testing"
+)
+
+func TestNumberFormat(t *testing.T) {
+	samples := []struct {
+		f        float64
+		nf       NumberFormat
+		expected string
+	}{
+		{0, NumberFormat{}, "0"},
+		{3.14159, NumberFormat{}, "3.1"},
+		{12345.67, NumberFormat{}, "12k"},
+		{0.09876, NumberFormat{}, "99m"},
+		{-0.09876, NumberFormat{}, "-99m"},
+		{12345.67, NumberFormat{Locale: EnglishLocale}, "12 k"},
+		{3.14159, NumberFormat{Locale: GermanLocale}, "3,1"},
+		{1234567.891, NumberFormat{Style: FixedNumber, Precision: 2, Locale: GermanLocale}, "1.234.567,89"},
+		{-1234567.891, NumberFormat{Style: FixedNumber, Precision: 2, Locale: EnglishLocale}, "-1,234,567.89"},
+		{123456, NumberFormat{Style: FixedNumber, Locale: SwissLocale}, "123'456"},
+		{999.9, NumberFormat{Style: FixedNumber, Locale: GermanLocale}, "1.000"},
+		{12345.67, NumberFormat{Style: ScientificNumber, Precision: 2}, "1.23e4"},
+		{0.0012345, NumberFormat{Style: ScientificNumber, Precision: 1, Locale: GermanLocale}, "1,2e-3"},
+		{9.999, NumberFormat{Style: ScientificNumber, Precision: 1}, "1.0e1"},
+		{12345.67, NumberFormat{Style: EngineeringNumber, Precision: 1}, "12.3e3"},
+		{0.0012345, NumberFormat{Style: EngineeringNumber, Precision: 2}, "1.23e-3"},
+		{-0.00012345, NumberFormat{Style: EngineeringNumber, Precision: 0}, "-123e-6"},
+		{0.1234, NumberFormat{Style: PercentNumber, Precision: 1, Locale: GermanLocale}, "12,3 %"},
+		{0.5, NumberFormat{Style: PercentNumber}, "50%"},
+		{1234.5, NumberFormat{Style: CurrencyNumber, Precision: 2, Locale: GermanLocale, Currency: "€", CurrencyAfter: true}, "1.234,50 €"},
+		{1234.5, NumberFormat{Style: CurrencyNumber, Precision: 2, Locale: Locale{Grouping: ","}, Currency: "$"}, "$1,234.50"},
+		{-12, NumberFormat{Style: CurrencyNumber, Currency: "$"}, "-$12"},
+	}
+
+	for i, s := range samples {
+		if got := s.nf.Format(s.f); got != s.expected {
+			t.Errorf("%d: Format(%g) = %q, expected %q", i, s.f, got, s.expected)
+		}
+	}
+
+	nf := NumberFormat{Style: PercentNumber, Locale: GermanLocale}
+	if got := nf.Share(1, 4); got != "25 %" {
+		t.Errorf("Share(1,4) = %q, expected %q", got, "25 %")
+	}
+}
@@ -0,0 +1,211 @@
+package chart
+
+import (
+	"image/color"
+	"math"
+)
+
+// GaugeBand is a colored range on the scale of a GaugeChart, e.g. to mark
+// warning or critical thresholds.
+type GaugeBand struct {
+	From, To float64
+	Name     string // shown in the key if non empty
+	Style    Style
+}
+
+// GaugeChart shows a single value against a scale from Min to Max on a half
+// or partial ring. The value is indicated by an arc filled from Min to Value
+// or by a needle. Bands color ranges of the scale.
+type GaugeChart struct {
+	Title    string  // The title
+	Key      Key     // The Key/Legend, lists the named bands
+	Min, Max float64 // The scale
+	Value    float64 // The value to show, clipped to [Min,Max]
+	Angle    float64 // angle spanned by the gauge in degrees, 0 is 180 (half ring)
+	Inner    float64 // relative radius of inner border of the ring, 0 is 0.6
+	Needle   bool    // show needle instead of filled arc
+	Style    Style   // style of arc or needle, empty: color of band containing Value, blue or dark gray
+	Label    string  // text shown below the value, e.g. its unit
+	Options  PlotOptions
+	Bands    []GaugeBand
+
+	FmtVal func(float64) string // format value and scale labels, nil uses FmtFloat
+}
+
+// AddBand adds a band from from to to. An empty style is generated by
+// AutoStyle.
+func (c *GaugeChart) AddBand(from, to float64, name string, style Style) {
+	if style.empty() {
+		style = AutoStyle(len(c.Bands), true)
+	}
+	c.Bands = append(c.Bands, GaugeBand{From: from, To: to, Name: name, Style: style})
+	if name != "" {
+		c.Key.Entries = append(c.Key.Entries, KeyEntry{PlotStyle: PlotStyleBox, Style: style, Text: name})
+	}
+}
+
+// Reset chart to state before plotting.
+func (c *GaugeChart) Reset() {}
+
+// Plot outputs the gauge chart c to g.
+func (c *GaugeChart) Plot(g Graphics) {
+	layout := layout(g, c.Title, nil, nil, &c.Key, c.Options)
+
+	width, height := layout.Width, layout.Height
+	topm, leftm := layout.Top, layout.Left
+
+	fw, fh, _ := g.FontMetrics(Font{})
+	vfont := Font{Size: HugeFontSize}
+	_, vfh, _ := g.FontMetrics(vfont)
+	ecc := circleStretch(g)
+
+	angle := c.Angle
+	if angle <= 0 {
+		angle = 180
+	}
+	span := math.Min(angle, 359) * math.Pi / 180
+	a0 := -math.Pi/2 - span/2 // start of scale, the gauge is symmetric to the vertical
+	inner := c.Inner
+	if inner <= 0 {
+		inner = 0.6
+	}
+	fmtv := c.FmtVal
+	if fmtv == nil {
+		fmtv = FmtFloat
+	}
+	smin, smax := fmtv(c.Min), fmtv(c.Max)
+
+	// Determine radius r: The scale labels are below the ends of the ring,
+	// the value (and label) in the center, below the hub of the needle.
+	lw := imax(g.TextLen(smin, Font{}), g.TextLen(smax, Font{}))
+	below := math.Max(0, -math.Cos(span/2))
+	textBelow := 2 * fh
+	if c.Needle {
+		below += 0.1
+		textBelow = vfh + fh/2
+		if c.Label != "" {
+			textBelow += fh
+		}
+		textBelow = imax(textBelow, 2*fh)
+	}
+	rw := (float64(width-lw) - 2*float64(fw)) / (2 * ecc)
+	rh := float64(height-textBelow-fh) / (1 + below)
+	r := imax(int(math.Min(rw, rh)), 2*fh)
+	used := r + imax(int(below*float64(r)), textBelow)
+	x0, y0 := leftm+width/2, topm+(height-used)/2+r
+	ro, ri := r, int(inner*float64(r))
+
+	ang := func(v float64) float64 {
+		if c.Max == c.Min {
+			return a0
+		}
+		f := (v - c.Min) / (c.Max - c.Min)
+		return a0 + span*math.Max(0, math.Min(1, f))
+	}
+	av := ang(c.Value)
+
+	g.Begin()
+
+	if c.Title != "" {
+		drawTitle(g, c.Title, elementStyle(c.Options, TitleElement))
+	}
+
+	track := Style{Symbol: '.', LineColor: color.NRGBA{0xa0, 0xa0, 0xa0, 0xff}, LineWidth: 1, LineStyle: SolidLine,
+		FillColor: color.NRGBA{0xe8, 0xe8, 0xe8, 0xff}}
+	if c.Needle {
+		g.Wedge(x0, y0, ro, ri, a0, a0+span, track)
+		c.drawBands(g, x0, y0, ro, ri, ang)
+
+		style := c.Style
+		if style.empty() {
+			style = Style{Symbol: '*', LineColor: color.NRGBA{0x40, 0x40, 0x40, 0xff}, LineStyle: SolidLine,
+				FillColor: color.NRGBA{0x40, 0x40, 0x40, 0xff}}
+		}
+		if style.LineWidth == 0 {
+			style.LineWidth = imax(2, r/30)
+		}
+		rn := float64(ro - (ro-ri)/4)
+		g.Line(x0, y0, x0+int(rn*math.Cos(av)*ecc+0.5), y0+int(rn*math.Sin(av)+0.5), style)
+		hub := imax(2, r/10)
+		hubStyle := style
+		hubStyle.LineWidth = 1
+		g.Wedge(x0, y0, hub, 0, 0, math.Pi, hubStyle)
+		g.Wedge(x0, y0, hub, 0, math.Pi, 2*math.Pi, hubStyle)
+	} else {
+		// Bands are drawn as thin outer ring.
+		rt := ro
+		if len(c.Bands) > 0 {
+			bri := ro - imax(1, (ro-ri)/5)
+			c.drawBands(g, x0, y0, ro, bri, ang)
+			rt = bri - imax(1, (ro-ri)/20)
+		}
+		g.Wedge(x0, y0, rt, ri, a0, a0+span, track)
+
+		style := c.Style
+		if style.empty() {
+			style = Style{Symbol: '*', LineColor: color.NRGBA{0x30, 0x60, 0xb0, 0xff}, LineWidth: 1, LineStyle: SolidLine,
+				FillColor: color.NRGBA{0x60, 0x90, 0xe0, 0xff}}
+			for _, b := range c.Bands {
+				if c.Value >= math.Min(b.From, b.To) && c.Value <= math.Max(b.From, b.To) {
+					style = b.Style
+				}
+			}
+		}
+		if av > a0 {
+			g.Wedge(x0, y0, rt, ri, a0, av, style)
+		}
+	}
+
+	// Scale labels below the ends of the ring.
+	for i, a := range []float64{a0, a0 + span} {
+		sa := math.Sin(a)
+		rl := float64(ri)
+		if sa > 0 {
+			rl = float64(ro)
+		}
+		tx := x0 + int(float64(ri+ro)/2*math.Cos(a)*ecc+0.5)
+		ty := y0 + int(rl*sa+0.5) + imax(1, fh/3)
+		t := smin
+		if i == 1 {
+			t = smax
+		}
+		g.Text(tx, ty, t, "tc", 0, Font{})
+	}
+
+	// The value and its label.
+	vs := fmtv(c.Value)
+	var vy int
+	switch {
+	case c.Needle:
+		vy = y0 + imax(2, r/10) + vfh/2 + 1
+	case span <= math.Pi:
+		vy = y0 - vfh/2
+	default:
+		vy = y0
+	}
+	g.Text(x0, vy, vs, "cc", 0, vfont)
+	if c.Label != "" {
+		ly := vy + vfh/2 + imax(1, fh/3)
+		if !c.Needle && span <= math.Pi {
+			ly = y0 + imax(1, fh/3)
+		}
+		g.Text(x0, ly, c.Label, "tc", 0, Font{})
+	}
+
+	if !c.Key.Hide {
+		g.Key(layout.KeyX, layout.KeyY, c.Key, c.Options)
+	}
+
+	g.End()
+}
+
+// drawBands draws the bands of c as ring segments with radii ro and ri
+// centered at (x,y). The function ang maps values to angles.
+func (c *GaugeChart) drawBands(g Graphics, x, y, ro, ri int, ang func(float64) float64) {
+	for _, b := range c.Bands {
+		phi, psi := ang(math.Min(b.From, b.To)), ang(math.Max(b.From, b.To))
+		if psi > phi {
+			g.Wedge(x, y, ro, ri, phi, psi, b.Style)
+		}
+	}
+}
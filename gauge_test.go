@@ -0,0 +1,102 @@
+package chart_test
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/vdobler/chart"
+	"github.com/vdobler/chart/txtg"
+)
+
+// find returns the row and the center column of the first occurrence of s
+// in the lines of text or -1, -1.
+func find(text []string, s string) (row, col int) {
+	for i, line := range text {
+		if j := strings.Index(line, s); j >= 0 {
+			return i, j + len(s)/2
+		}
+	}
+	return -1, -1
+}
+
+func TestGaugeChart(t *testing.T) {
+	for _, needle := range []bool{false, true} {
+		c := chart.GaugeChart{Min: 0, Max: 100, Value: 75, Label: "%", Needle: needle}
+		c.AddBand(80, 100, "high", chart.Style{Symbol: 'o', FillColor: color.Black})
+		c.Key.Hide = true
+		g := txtg.New(60, 20)
+		c.Plot(g)
+		text := strings.Split(g.String(), "\n")
+
+		vr, vc := find(text, "75")
+		lr, lc := find(text, "%")
+		r0, c0 := find(text, " 0 ")
+		r1, c1 := find(text, "100")
+		if vr < 0 || lr != vr+1 || lc != vc {
+			t.Errorf("needle=%t: value at %d,%d, label at %d,%d\n%s", needle, vr, vc, lr, lc, g)
+		}
+		if d := (vc - c0) - (c1 - vc); r0 < 0 || r0 != r1 || d < -1 || d > 1 {
+			t.Errorf("needle=%t: scale labels at %d,%d and %d,%d not symmetric to %d\n%s",
+				needle, r0, c0, r1, c1, vc, g)
+		}
+		if !needle && vr >= r0 || needle && vr <= r0 {
+			t.Errorf("needle=%t: value in row %d, scale labels in row %d\n%s", needle, vr, r0, g)
+		}
+
+		// Value 75 lies in the upper right, the band in the right quarter.
+		var left, right, band int
+		for _, line := range text[:r0] {
+			for x, ch := range line {
+				switch {
+				case ch == '*' && x < vc-1:
+					left++
+				case ch == '*' && x > vc+1:
+					right++
+				case ch == 'o' && x < vc:
+					band++
+				}
+			}
+		}
+		if left == 0 || right == 0 || band != 0 {
+			t.Errorf("needle=%t: arc/needle %d left, %d right, %d band cells left\n%s", needle, left, right, band, g)
+		}
+	}
+}
+
+func TestRingCenterLabel(t *testing.T) {
+	c := chart.PieChart{Inner: 0.5, Center: "42\nkW"}
+	c.Key.Hide = true
+	c.AddDataPair("", []string{"A", "B", "C"}, []float64{1, 2, 3})
+
+	// The center label is drawn by the chart, not passed to Rings.
+	rr := &ringRecorder{TextGraphics: txtg.New(60, 24)}
+	c.Plot(rr)
+	if len(rr.rings) != 1 || strings.Count(rr.rings[0], "..") != 3 {
+		t.Errorf("unexpected rings %q", rr.rings)
+	}
+	if !strings.Contains(rr.String(), "42") {
+		t.Errorf("missing center label\n%s", rr.String())
+	}
+
+	// Both lines are centered in the ring, also when text drivers shift
+	// the pie by a changed stretch factor.
+	defer func(f float64) { txtg.CircleStretchFactor = f }(txtg.CircleStretchFactor)
+	for _, f := range []float64{txtg.CircleStretchFactor, 2.5} {
+		txtg.CircleStretchFactor = f
+		g := txtg.New(70, 24)
+		c.Plot(g)
+		text := strings.Split(g.String(), "\n")
+		r1, c1 := find(text, "42")
+		r2, c2 := find(text, "kW")
+		if r1 < 0 || r2 != r1+1 || c2 != c1 {
+			t.Fatalf("stretch %g: center label at %d,%d and %d,%d\n%s", f, r1, c1, r2, c2, g)
+		}
+		line := text[r1]
+		left := len(line) - len(strings.TrimLeft(line, " "))
+		right := len(strings.TrimRight(line, " ")) - 1
+		if mid := (left + right) / 2; c1 < mid-1 || c1 > mid+1 {
+			t.Errorf("stretch %g: center label in column %d, ring centered at %d\n%s", f, c1, mid, g)
+		}
+	}
+}
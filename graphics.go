@@ -53,10 +53,12 @@ type Graphics interface {
 
 // Barinfo describes a rectangular bar (e.g. in a histogram or a bar plot).
 type Barinfo struct {
-	x, y  int    // (x,y) of top left corner;
-	w, h  int    // width and heigt
-	t, tp string // label text and text position '[oi][tblr]' or 'c'
-	f     Font   // font of text
+	x, y   int    // (x,y) of top left corner;
+	w, h   int    // width and heigt
+	t, tp  string // label text and text position '[oi][tblr]' or 'c'
+	f      Font   // font of text
+	err    bool   // draw error bar
+	e0, e1 int    // y coordinates of ends of error bar
 }
 
 // Wedgeinfo describes a wedge in a pie chart.
@@ -137,7 +139,159 @@ func GenericPath(mg MinimalGraphics, x, y []int, style Style) {
 	}
 }
 
-func drawXTics(bg BasicGraphics, rng Range, y, ym, ticLen int, options PlotOptions) {
+// Ellipsis is appended to tic labels shortened during label layout.
+var Ellipsis = "…"
+
+// LabelPlacement is the result of the layout of the tic labels of an x axis.
+type LabelPlacement struct {
+	Tics   []Tic // copy of the tics of the axis, possibly with thinned out or truncated labels
+	Row    []int // row (0 or 1) of each tic label
+	Rot    int   // rotation of all labels in degree: 0 or 90
+	Height int   // vertical space needed by the labels in screen units
+}
+
+// labelExtent is the horizontal extent of a single tic label on the screen.
+type labelExtent struct {
+	idx         int // index into tics
+	pos         int // screen position of label
+	left, right int // extent of label
+	width       int // width of label
+}
+
+// collide reports whether any two consecutive labels of ext selected by
+// use overlap or are less than gap apart.
+func collide(ext []labelExtent, use func(i int) bool, gap int) bool {
+	last := -1
+	for i, e := range ext {
+		if !use(i) {
+			continue
+		}
+		if last != -1 && ext[last].right+gap > e.left {
+			return true
+		}
+		last = i
+	}
+	return false
+}
+
+// PlaceXTicLabels lays out the tic labels of the x axis rng if they
+// would be printed in font on mg. Colliding labels are rotated, staggered,
+// thinned out or truncated as allowed by rng.TicSetting.LabelLayout (see
+// LabelLayout for the order and the fallback).
+// Range rng must have been set up already.
+func PlaceXTicLabels(mg MinimalGraphics, rng Range, font Font) (lp LabelPlacement) {
+	fw, fh, _ := mg.FontMetrics(font)
+	gap := imax(1, int(fw+0.5))
+	lp.Tics = make([]Tic, len(rng.Tics))
+	copy(lp.Tics, rng.Tics)
+	lp.Row = make([]int, len(rng.Tics))
+	lp.Height = fh
+
+	mode := rng.TicSetting.LabelLayout
+	if mode == 0 || mode&LabelOverlap != 0 || rng.TicSetting.HideLabels {
+		return
+	}
+
+	// Extents of all non-empty labels
+	ext := make([]labelExtent, 0, len(lp.Tics))
+	for i, tic := range lp.Tics {
+		if tic.Label == "" {
+			continue
+		}
+		x := rng.Data2Screen(tic.LabelPos)
+		w := mg.TextLen(tic.Label, font)
+		e := labelExtent{idx: i, pos: x, left: x - w/2, right: x + w - w/2, width: w}
+		if rng.Time && tic.Align == -1 {
+			e.left, e.right = x, x+w
+		}
+		ext = append(ext, e)
+	}
+	all := func(int) bool { return true }
+	if !collide(ext, all, gap) {
+		return
+	}
+
+	// Rotation: all labels are fh wide
+	if mode&LabelRotate != 0 {
+		rot := make([]labelExtent, len(ext))
+		height := 0
+		for i, e := range ext {
+			rot[i] = labelExtent{idx: e.idx, pos: e.pos, left: e.pos - fh/2, right: e.pos + fh - fh/2}
+			height = imax(height, e.width)
+		}
+		if !collide(rot, all, gap) || mode&^LabelRotate == 0 {
+			lp.Rot, lp.Height = 90, height
+			return
+		}
+	}
+
+	// Staggering: labels on the same row must not collide
+	if mode&LabelStagger != 0 {
+		even := func(i int) bool { return i%2 == 0 }
+		odd := func(i int) bool { return i%2 == 1 }
+		if (!collide(ext, even, gap) && !collide(ext, odd, gap)) || mode&(LabelThin|LabelTruncate) == 0 {
+			for i, e := range ext {
+				lp.Row[e.idx] = i % 2
+			}
+			lp.Height = 2 * fh
+			return
+		}
+	}
+
+	// Thinning: label only every n-th tic
+	if mode&LabelThin != 0 {
+		n := 2
+		for ; n < len(ext); n++ {
+			nth := func(i int) bool { return i%n == 0 }
+			if !collide(ext, nth, gap) {
+				break
+			}
+		}
+		// Thinning out all but one label would be pointless if truncation is possible.
+		if n < len(ext) || mode&LabelTruncate == 0 {
+			for i, e := range ext {
+				if i%n != 0 {
+					lp.Tics[e.idx].Label = ""
+				}
+			}
+			return
+		}
+	}
+
+	// Truncation: shorten each label to the space available between its neighbours.
+	for i, e := range ext {
+		avail := -1
+		if i > 0 {
+			avail = e.pos - ext[i-1].pos - gap
+		}
+		if i < len(ext)-1 {
+			d := ext[i+1].pos - e.pos - gap
+			if avail == -1 || d < avail {
+				avail = d
+			}
+		}
+		if avail < 0 || e.width <= avail {
+			continue
+		}
+		lp.Tics[e.idx].Label = truncateLabel(mg, lp.Tics[e.idx].Label, avail, font)
+	}
+	return
+}
+
+// truncateLabel shortens label and appends Ellipsis so that it is at most
+// width wide. If not even a single character fits, "" is returned.
+func truncateLabel(mg MinimalGraphics, label string, width int, font Font) string {
+	r := []rune(label)
+	for n := len(r) - 1; n > 0; n-- {
+		t := string(r[:n]) + Ellipsis
+		if mg.TextLen(t, font) <= width {
+			return t
+		}
+	}
+	return ""
+}
+
+func drawXTics(bg BasicGraphics, rng Range, lp LabelPlacement, y, ym, ticLen int, options PlotOptions) {
 	xe := rng.Data2Screen(rng.Max)
 
 	// Grid below tics
@@ -161,9 +315,11 @@ func drawXTics(bg BasicGraphics, rng Range, y, ym, ticLen int, options PlotOptio
 	// Tics on top
 	ticstyle := elementStyle(options, MajorTicElement)
 	ticfont := ticstyle.Font
-	for _, tic := range rng.Tics {
+	_, fh, _ := bg.FontMetrics(ticfont)
+	for i, tic := range lp.Tics {
 		x := rng.Data2Screen(tic.Pos)
 		lx := rng.Data2Screen(tic.LabelPos)
+		ly := y + 2*ticLen + lp.Row[i]*fh
 
 		// Tics
 		switch rng.TicSetting.Tics {
@@ -193,9 +349,16 @@ func drawXTics(bg BasicGraphics, rng Range, y, ym, ticLen int, options PlotOptio
 			// Tic-Label
 			if rng.Time && tic.Align == -1 {
 				bg.Line(x, y+ticLen, x, y+2*ticLen, ticstyle)
-				bg.Text(lx, y+2*ticLen, tic.Label, "tl", 0, ticfont)
+			}
+			if tic.Label == "" {
+				continue
+			}
+			if lp.Rot != 0 {
+				bg.Text(lx, ly, tic.Label, "cr", lp.Rot, ticfont)
+			} else if rng.Time && tic.Align == -1 {
+				bg.Text(lx, ly, tic.Label, "tl", 0, ticfont)
 			} else {
-				bg.Text(lx, y+2*ticLen, tic.Label, "tc", 0, ticfont)
+				bg.Text(lx, ly, tic.Label, "tc", 0, ticfont)
 			}
 		}
 	}
@@ -210,11 +373,12 @@ func GenericXAxis(bg BasicGraphics, rng Range, y, ym int, options PlotOptions) {
 		ticLen = imin(12, imax(4, fontheight/2))
 	}
 	xa, xe := rng.Data2Screen(rng.Min), rng.Data2Screen(rng.Max)
+	lp := PlaceXTicLabels(bg, rng, elementStyle(options, MajorTicElement).Font)
 
 	// Axis label and range limits
 	aly := y + 2*ticLen
 	if !rng.TicSetting.Hide {
-		aly += (3 * fontheight) / 2
+		aly += lp.Height + fontheight/2
 	}
 	if rng.ShowLimits {
 		font := elementStyle(options, RangeLimitElement).Font
@@ -233,7 +397,7 @@ func GenericXAxis(bg BasicGraphics, rng Range, y, ym int, options PlotOptions) {
 
 	// Tics and Grid
 	if !rng.TicSetting.Hide {
-		drawXTics(bg, rng, y, ym, ticLen, options)
+		drawXTics(bg, rng, lp, y, ym, ticLen, options)
 	}
 
 	// Axis itself, mirrord axis and zero
@@ -410,47 +574,120 @@ func GenericScatter(bg BasicGraphics, points []EPoint, plotstyle PlotStyle, styl
 }
 
 // GenericBoxes draws box plots. (Default implementation for box plots).
-// The values for each box in boxes are in screen coordinates!
+// The values for each box in boxes are in screen coordinates! For boxes with
+// Horizontal set X is the vertical position of a box and the values are
+// horizontal positions. Boxes with NotchLow != NotchHigh are notched.
 func GenericBoxes(bg BasicGraphics, boxes []Box, width int, style Style) {
 	if width%2 == 0 {
 		width++
 	}
 	hbw := (width - 1) / 2
+
 	for _, d := range boxes {
+		// Work in box coordinates: v across the box, u along the values.
+		horizontal := d.Horizontal
+		pt := func(v, u int) (int, int) {
+			if horizontal {
+				return u, v
+			}
+			return v, u
+		}
+		line := func(v0, u0, v1, u1 int, style Style) {
+			x0, y0 := pt(v0, u0)
+			x1, y1 := pt(v1, u1)
+			bg.Line(x0, y0, x1, y1, style)
+		}
+		symbol := func(v, u int) {
+			x, y := pt(v, u)
+			bg.Symbol(x, y, style)
+		}
+
 		x := int(d.X)
 		q1, q3 := int(d.Q1), int(d.Q3)
 		// DebugLogger.Printf("q1=%d  q3=%d  q3-q1=%d", q1,q3,q3-q1)
-		bg.Rect(x-hbw, q1, width, q3-q1, style)
+		notched := d.NotchLow != d.NotchHigh && !math.IsNaN(d.Med)
+		ind := 0 // indentation of notch
+		switch {
+		case notched:
+			ind = hbw / 2
+			genericNotchedBox(bg, x, hbw, q1, q3, int(d.Med), int(d.NotchLow), int(d.NotchHigh), pt, style)
+		case horizontal:
+			bg.Rect(q1, x-hbw, q3-q1, width, style)
+		default:
+			bg.Rect(x-hbw, q1, width, q3-q1, style)
+		}
 		if !math.IsNaN(d.Med) {
 			med := int(d.Med)
-			bg.Line(x-hbw, med, x+hbw, med, style)
+			line(x-hbw+ind, med, x+hbw-ind, med, style)
 		}
 
 		if !math.IsNaN(d.Avg) {
-			bg.Symbol(x, int(d.Avg), style)
+			symbol(x, int(d.Avg))
 		}
 
 		if !math.IsNaN(d.High) {
-			bg.Line(x, q3, x, int(d.High), style)
+			line(x, q3, x, int(d.High), style)
 		}
 
 		if !math.IsNaN(d.Low) {
-			bg.Line(x, q1, x, int(d.Low), style)
+			line(x, q1, x, int(d.Low), style)
 		}
 
 		for _, y := range d.Outliers {
-			bg.Symbol(x, int(y), style)
+			symbol(x, int(y))
 		}
 
 	}
 
 }
 
+// genericNotchedBox draws the box from q1 to q3 of half width hbw around x
+// with a notch from n1 to n2 around med. The function pt maps box
+// coordinates to screen coordinates.
+func genericNotchedBox(bg BasicGraphics, x, hbw, q1, q3, med, n1, n2 int, pt func(v, u int) (int, int), style Style) {
+	lo, hi := imin(q1, q3), imax(q1, q3)
+	n1, n2 = clip(imin(n1, n2), lo, hi), clip(imax(n1, n2), lo, hi)
+	med = clip(med, n1, n2)
+	ind := hbw / 2
+
+	// indent returns the indentation of the box border at u.
+	indent := func(u int) int {
+		switch {
+		case u <= n1 || u >= n2:
+			return 0
+		case u <= med:
+			return ind * (u - n1) / imax(1, med-n1)
+		}
+		return ind * (n2 - u) / imax(1, n2-med)
+	}
+
+	if style.FillColor != nil {
+		fs := Style{LineWidth: 1, LineColor: style.FillColor, LineStyle: SolidLine}
+		for u := lo + 1; u < hi; u++ {
+			i := indent(u)
+			x0, y0 := pt(x-hbw+i+1, u)
+			x1, y1 := pt(x+hbw-i-1, u)
+			bg.Line(x0, y0, x1, y1, fs)
+		}
+	}
+
+	vs := []int{x - hbw, x - hbw, x - hbw + ind, x - hbw, x - hbw, x + hbw, x + hbw, x + hbw - ind, x + hbw, x + hbw, x - hbw}
+	us := []int{lo, n1, med, n2, hi, hi, n2, med, n1, lo, lo}
+	px, py := make([]int, len(vs)), make([]int, len(vs))
+	for i := range vs {
+		px[i], py[i] = pt(vs[i], us[i])
+	}
+	bg.Path(px, py, style)
+}
+
 // GenericBars draws the bars in the given style using bg.
 // TODO: Is Bars and Generic Bars useful at all? Replaceable by rect?
 func GenericBars(bg BasicGraphics, bars []Barinfo, style Style) {
 	for _, b := range bars {
 		bg.Rect(b.x, b.y, b.w, b.h, style)
+		if b.err {
+			genericErrorBar(bg, b, style)
+		}
 		if b.t != "" {
 			var tx, ty int
 			var a string
@@ -485,8 +722,40 @@ func GenericBars(bg BasicGraphics, bars []Barinfo, style Style) {
 	}
 }
 
+// genericErrorBar draws the error bar of b as a vertical whisker with caps.
+func genericErrorBar(bg BasicGraphics, b Barinfo, style Style) {
+	es := Style{Symbol: '|', LineColor: style.LineColor, LineWidth: 1, LineStyle: SolidLine}
+	if es.LineColor == nil {
+		es.LineColor = color.NRGBA{0x40, 0x40, 0x40, 0xff}
+	}
+	if style.LineWidth > 2 {
+		es.LineWidth = style.LineWidth / 2
+	}
+	x := b.x + b.w/2
+	bg.Line(x, b.e0, x, b.e1, es)
+	es.Symbol = '-'
+	cw := imax(1, b.w/4)
+	bg.Line(x-cw, b.e0, x+cw, b.e0, es)
+	bg.Line(x-cw, b.e1, x+cw, b.e1, es)
+}
+
+// circleStretch returns the factor by which the driver g stretches circles
+// horizontally (e.g. to compensate for non-square character cells). Drivers
+// report it by implementing CircleStretch() float64, the default is 1.
+func circleStretch(g MinimalGraphics) float64 {
+	if s, ok := g.(interface{ CircleStretch() float64 }); ok {
+		return s.CircleStretch()
+	}
+	return 1
+}
+
 // GenericWedge draws a pie/wedge just by lines
 func GenericWedge(mg MinimalGraphics, x, y, ro, ri int, phi, psi, ecc float64, style Style) {
+	// The code below runs counterclockwise on screen while angles in
+	// Wedgeinfo run clockwise (screen y is downwards): mirror the wedge.
+	// Mirror at 2pi, not at 0: -0 would not be normalized below and
+	// fillWedge would never reach psi.
+	phi, psi = 2*math.Pi-psi, 2*math.Pi-phi
 	for phi < 0 {
 		phi += 2 * math.Pi
 	}
@@ -684,6 +953,10 @@ func GenericRings(bg BasicGraphics, wedges []Wedgeinfo, x, y, ro, ri int, eccent
 		SinDelta := math.Sin(delta)
 		gamma := (w.Phi + w.Psi) / 2
 		k := d / SinDelta
+		if ri > 0 {
+			// Ring segments have no common apex: No need to move them apart.
+			k = 0
+		}
 		shift := float64(w.Shift)
 		kx, ky := (k+shift)*math.Cos(gamma), (k+shift)*math.Sin(gamma)
 
@@ -691,10 +964,10 @@ func GenericRings(bg BasicGraphics, wedges []Wedgeinfo, x, y, ro, ri int, eccent
 			w.Style.LineWidth, d, int(180*w.Phi/math.Pi), int(180*w.Psi/math.Pi), kx, ky, k)
 
 		xi, yi := x+int(kx+0.5), y+int(ky+0.5)
-		roc, ric := ro-int(d+k), ri-int(d+k)
+		roc, ric := ro-int(d+k), imax(0, ri-int(d+k)) // pie wedges have no inner radius
 		bg.Wedge(xi, yi, roc, ric, w.Phi, w.Psi, w.Style)
 
-		if w.Text != "" {
+		if w.Text != "" && w.Tp != "o" {
 			_, fh, _ := bg.FontMetrics(w.Font)
 			fh += 0
 			alpha := (w.Phi + w.Psi) / 2
@@ -716,6 +989,98 @@ func GenericRings(bg BasicGraphics, wedges []Wedgeinfo, x, y, ro, ri int, eccent
 
 	}
 
+	GenericPieLabels(bg, wedges, x, y, ro, eccentricity)
+}
+
+// PieLabelLead returns the length of the leader lines of outside pie labels
+// for font height fh.
+func PieLabelLead(fh int) int {
+	return imax(3, fh)
+}
+
+// GenericPieLabels draws the labels of all wedges with text position "o"
+// outside the pie with center (x,y) and radius ro. The labels are stacked
+// on the left and right side of the pie without overlap and connected to
+// their wedge by a leader line.
+func GenericPieLabels(mg MinimalGraphics, wedges []Wedgeinfo, x, y, ro int, eccentricity float64) {
+	type pieLabel struct {
+		w      Wedgeinfo
+		ax, ay int // anchor on wedge
+		ex, ly int // elbow of leader line and vertical label position
+	}
+	var left, right []pieLabel
+	for _, w := range wedges {
+		if w.Text == "" || w.Tp != "o" {
+			continue
+		}
+		_, fh, _ := mg.FontMetrics(w.Font)
+		lead := PieLabelLead(fh)
+		alpha := (w.Phi + w.Psi) / 2
+		ca, sa := math.Cos(alpha), math.Sin(alpha)
+		r := float64(ro + w.Shift)
+		l := pieLabel{w: w,
+			ax: x + int(r*ca*eccentricity+0.5), ay: y + int(r*sa+0.5),
+			ex: x + int((r+float64(lead)/2)*ca*eccentricity+0.5), ly: y + int((r+float64(lead))*sa+0.5),
+		}
+		if ca >= 0 {
+			right = append(right, l)
+		} else {
+			left = append(left, l)
+		}
+	}
+
+	for side, labels := range [][]pieLabel{left, right} {
+		if len(labels) == 0 {
+			continue
+		}
+		// Sort by y and push overlapping labels apart: first downwards,
+		// then upwards if the lowest label is too far below the pie.
+		for i := 1; i < len(labels); i++ {
+			for j := i; j > 0 && labels[j].ly < labels[j-1].ly; j-- {
+				labels[j], labels[j-1] = labels[j-1], labels[j]
+			}
+		}
+		seps := make([]int, len(labels))
+		for i, l := range labels {
+			_, fh, _ := mg.FontMetrics(l.w.Font)
+			seps[i] = imax(1, fh+fh/4)
+		}
+		for i := 1; i < len(labels); i++ {
+			if lo := labels[i-1].ly + seps[i]; labels[i].ly < lo {
+				labels[i].ly = lo
+			}
+		}
+		n := len(labels) - 1
+		_, fh, _ := mg.FontMetrics(labels[n].w.Font)
+		if hi := y + ro + PieLabelLead(fh) + fh; labels[n].ly > hi {
+			labels[n].ly = hi
+			for i := n - 1; i >= 0; i-- {
+				if hi := labels[i+1].ly - seps[i+1]; labels[i].ly > hi {
+					labels[i].ly = hi
+				}
+			}
+		}
+
+		for _, l := range labels {
+			fw, fh, _ := mg.FontMetrics(l.w.Font)
+			lead := PieLabelLead(fh)
+			style := l.w.Style
+			style.LineWidth, style.LineStyle, style.Symbol = 1, SolidLine, '.'
+			if style.LineColor == nil {
+				style.LineColor = style.FillColor
+			}
+			lx, align, gap := x+int(float64(ro)*eccentricity)+lead, "cl", imax(1, int(fw/2))
+			if side == 0 {
+				lx, align, gap = x-int(float64(ro)*eccentricity)-lead, "cr", -gap
+			}
+			if (side == 0 && l.ex < lx) || (side == 1 && l.ex > lx) {
+				lx = l.ex
+			}
+			mg.Line(l.ax, l.ay, l.ex, l.ly, style)
+			mg.Line(l.ex, l.ly, lx, l.ly, style)
+			mg.Text(lx+gap, l.ly, l.w.Text, align, 0, l.w.Font)
+		}
+	}
 }
 
 // GenericCircle approximates a circle of radius r around (x,y) with lines.
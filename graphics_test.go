@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"image/color"
 	"math"
+	"strings"
 	"testing"
 
 	"github.com/vdobler/chart"
@@ -61,3 +62,310 @@ func TestGenericWedge(t *testing.T) {
 	fmt.Printf("\n%s\n", g.String())
 
 }
+
+func TestGenericWedgeOrientation(t *testing.T) {
+	// Angles run clockwise on screen (y is downwards) starting at 3 o'clock:
+	// The quadrants are lower right, lower left, upper left and upper right.
+	s := chart.Style{Symbol: '#', FillColor: color.Black, LineColor: color.Black}
+	for q, want := range []string{"...#", "..#.", "#...", ".#.."} {
+		g := txtg.New(41, 21)
+		phi := float64(q)*math.Pi/2 + 0.1
+		chart.GenericWedge(g, 20, 10, 8, 0, phi, phi+math.Pi/2-0.2, 2, s)
+		var n [4]int // upper left, upper right, lower left, lower right
+		for y, line := range strings.Split(g.String(), "\n") {
+			for x, c := range line {
+				if c != '#' || x == 20 || y == 10 {
+					continue
+				}
+				i := 0
+				if x > 20 {
+					i++
+				}
+				if y > 10 {
+					i += 2
+				}
+				n[i]++
+			}
+		}
+		got := ""
+		for _, cnt := range n {
+			if cnt > 0 {
+				got += "#"
+			} else {
+				got += "."
+			}
+		}
+		if got != want {
+			t.Errorf("quadrant %d: got %s, want %s\n%s", q, got, want, g)
+		}
+	}
+
+	// A wedge ending exactly at 0 must terminate (gauges end there).
+	g := txtg.New(41, 21)
+	chart.GenericWedge(g, 20, 10, 8, 6, -math.Pi/2, 0, 2, s)
+	if !strings.Contains(g.String(), "#") {
+		t.Errorf("wedge ending at 0 not drawn\n%s", g)
+	}
+}
+
+func TestPlaceXTicLabels(t *testing.T) {
+	g := txtg.New(60, 10)
+	for _, mode := range []chart.LabelLayout{0, chart.LabelRotate, chart.LabelStagger,
+		chart.LabelThin, chart.LabelTruncate, chart.LabelAuto} {
+		rng := chart.Range{Category: []string{"Alpha", "Bravo", "Charlie", "Delta",
+			"Echo", "Foxtrott", "Golf", "Hotel", "India", "Juliett"}}
+		rng.TicSetting.LabelLayout = mode
+		rng.Fixed(-0.5, 9.5, 1)
+		rng.Init()
+		rng.Setup(10, 10, 50, 5, false)
+		lp := chart.PlaceXTicLabels(g, rng, chart.Font{})
+
+		if mode == 0 {
+			// The zero value keeps the labels as they are.
+			for i, tic := range lp.Tics {
+				if tic.Label != rng.Tics[i].Label || lp.Row[i] != 0 {
+					t.Errorf("Default layout changed tic %d: %q row %d", i, tic.Label, lp.Row[i])
+				}
+			}
+			if lp.Rot != 0 || lp.Height != 1 {
+				t.Errorf("Default layout: rotation %d, height %d", lp.Rot, lp.Height)
+			}
+			continue
+		}
+
+		last := -1
+		for i, tic := range lp.Tics {
+			if tic.Label == "" || lp.Rot != 0 || lp.Row[i] != 0 {
+				continue
+			}
+			x := rng.Data2Screen(tic.LabelPos)
+			w := g.TextLen(tic.Label, chart.Font{})
+			if x-w/2 <= last {
+				t.Errorf("Mode %d: label %q collides", mode, tic.Label)
+			}
+			last = x + w - w/2
+		}
+		switch mode {
+		case chart.LabelRotate:
+			if lp.Rot != 90 || lp.Height != 8 {
+				t.Errorf("Expected rotation by 90 and height 8, got %d and %d", lp.Rot, lp.Height)
+			}
+		case chart.LabelStagger:
+			if lp.Height != 2 {
+				t.Errorf("Expected two rows, got height %d", lp.Height)
+			}
+		case chart.LabelAuto:
+			if lp.Rot != 90 {
+				t.Errorf("Expected rotation to be tried first, got %d", lp.Rot)
+			}
+		}
+	}
+
+	// Rotated labels on a narrow axis still collide: Rotation is applied
+	// anyway if it is the last strategy, else the next one is tried.
+	for _, tc := range []struct {
+		mode chart.LabelLayout
+		rot  int
+	}{{chart.LabelRotate, 90}, {chart.LabelRotate | chart.LabelTruncate, 0}} {
+		rng := chart.Range{Category: []string{"Alpha", "Bravo", "Charlie", "Delta", "Echo"}}
+		rng.TicSetting.LabelLayout = tc.mode
+		rng.Fixed(-0.5, 4.5, 1)
+		rng.Init()
+		rng.Setup(5, 5, 6, 5, false)
+		if lp := chart.PlaceXTicLabels(g, rng, chart.Font{}); lp.Rot != tc.rot {
+			t.Errorf("Mode %d: got rotation %d, expected %d", tc.mode, lp.Rot, tc.rot)
+		}
+	}
+}
+
+func TestScatterBreaks(t *testing.T) {
+	// Every line segment is drawn by its own call to Scatter.
+	c := chart.ScatterChart{Gap: 2}
+	c.Key.Hide = true
+	c.AddDataPair("", []float64{0, 1, 2, 3, 4, 8, 9}, []float64{1, 2, math.NaN(), 2, 1, 2, 1},
+		chart.PlotStyleLines, chart.Style{Symbol: '-'})
+	c.AddFunc("", func(x float64) float64 {
+		if x > 3 && x < 6 {
+			return math.NaN()
+		}
+		return 1.5
+	}, chart.PlotStyleLines, chart.Style{Symbol: '*'})
+	g := &scatterRecorder{TextGraphics: txtg.New(60, 20)}
+	c.Plot(g)
+	if len(g.counts) != 5 || g.counts[0] != 2 || g.counts[1] != 2 || g.counts[2] != 2 {
+		t.Errorf("Got segments %v", g.counts)
+	}
+}
+
+// wedgeRecorder records center and radii of all wedges drawn.
+type wedgeRecorder struct {
+	*txtg.TextGraphics
+	wedges []string
+}
+
+func (r *wedgeRecorder) Wedge(x, y, ro, ri int, phi, psi float64, style chart.Style) {
+	r.wedges = append(r.wedges, fmt.Sprintf("%d,%d %d/%d", x, y, ro, ri))
+}
+
+func TestGenericRingsApex(t *testing.T) {
+	style := chart.Style{LineWidth: 2}
+	deg := math.Pi / 180
+	wedges := []chart.Wedgeinfo{{Phi: 0, Psi: 90 * deg, Style: style}, {Phi: 90 * deg, Psi: 100 * deg, Style: style}}
+
+	// Pie wedges share their apex: They are moved apart along their
+	// bisector by d/sin(delta) to keep their outlines from overlapping;
+	// the inner radius stays 0.
+	g := &wedgeRecorder{TextGraphics: txtg.New(10, 10)}
+	chart.GenericRings(g, wedges, 50, 50, 40, 0, 1)
+	if got, want := strings.Join(g.wedges, " "), "51,51 38/0 50,61 28/0"; got != want {
+		t.Errorf("pie: got %q, want %q", got, want)
+	}
+
+	// Ring segments have no common apex: Moving the thin 10° segment by
+	// 1/sin(5°) would shrink it by 12 pixel and its inner radius would
+	// become negative. They stay centered and are shrunk by the line width.
+	g = &wedgeRecorder{TextGraphics: txtg.New(10, 10)}
+	chart.GenericRings(g, wedges, 50, 50, 20, 10, 1)
+	if got, want := strings.Join(g.wedges, " "), "50,50 19/9 50,50 19/9"; got != want {
+		t.Errorf("ring: got %q, want %q", got, want)
+	}
+}
+
+// transpose returns the text s with rows and columns swapped.
+func transpose(s string) string {
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	var b strings.Builder
+	for x := range lines[0] {
+		for _, line := range lines {
+			b.WriteByte(line[x])
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func TestGenericBoxes(t *testing.T) {
+	style := chart.Style{Symbol: 'o', LineWidth: 1}
+	draw := func(b chart.Box, width int) string {
+		g := txtg.New(20, 20)
+		chart.GenericBoxes(g, []chart.Box{b}, width, style)
+		return g.String()
+	}
+	box := chart.Box{X: 6, Q1: 4, Q3: 14, Med: 9, Avg: 11, High: 17, Low: 1, Outliers: []float64{19}}
+
+	// A horizontal box is the transposed vertical box.
+	vertical := draw(box, 9)
+	box.Horizontal = true
+	if horizontal := draw(box, 9); transpose(horizontal) != vertical {
+		t.Errorf("horizontal box is not transposed vertical box\n%s\n%s", vertical, horizontal)
+	}
+
+	// Notches: The border of the box is indented by a quarter of the
+	// width at the median, not at the ends of the notch.
+	box.NotchLow, box.NotchHigh = 5, 13
+	for _, horizontal := range []bool{false, true} {
+		box.Horizontal = horizontal
+		text := draw(box, 9)
+		if horizontal {
+			text = transpose(text)
+		}
+		lines := strings.Split(text, "\n")
+		for _, tc := range []struct {
+			row  int
+			want string
+		}{{4, "  ooooooooo"}, {5, "  o       o"}, {9, "    ooooo"}, {13, "  o       o"}, {14, "  ooooooooo"}} {
+			if got := strings.TrimRight(lines[tc.row], " "); got != tc.want {
+				t.Errorf("horizontal=%t: row %d got %q, want %q", horizontal, tc.row, got, tc.want)
+			}
+		}
+	}
+
+	// Width is the full width of the box.
+	box = chart.Box{X: 6, Q1: 4, Q3: 14, Med: 9, Low: math.NaN(), High: math.NaN(), Avg: math.NaN()}
+	for _, w := range []int{3, 5, 9} {
+		if got := strings.TrimSpace(strings.Split(draw(box, w), "\n")[4]); len(got) != w {
+			t.Errorf("width %d: got %q", w, got)
+		}
+	}
+}
+
+func TestTextBoxes(t *testing.T) {
+	box := chart.Box{X: 6, Q1: 14, Q3: 4, Med: 9, Avg: 7, High: 1, Low: 17, NotchLow: 13, NotchHigh: 5}
+	for _, tc := range []struct {
+		horizontal bool
+		want       string
+	}{
+		{false, `
+      |
+      |
+      |
+  +-------+
+  \       /
+  |       |
+  |   o   |
+  |       |
+  >-------<
+  |       |
+  |       |
+  |       |
+  /       \
+  +-------+
+      |
+      |
+      |`},
+		{true, `
+
+    +\---v---/+
+    |    |    |
+    |    |    |
+    |    |    |
+ ---|  o |    |---
+    |    |    |
+    |    |    |
+    |    |    |
+    +/---^---\+`},
+	} {
+		box.Horizontal = tc.horizontal
+		g := txtg.New(20, 20)
+		g.Boxes([]chart.Box{box}, 9, chart.Style{Symbol: 'o'})
+		var lines []string
+		for _, line := range strings.Split(g.String(), "\n") {
+			lines = append(lines, strings.TrimRight(line, " "))
+		}
+		if got := strings.TrimRight(strings.Join(lines, "\n"), "\n"); got != tc.want {
+			t.Errorf("horizontal=%t: got\n%s\nwant\n%s", tc.horizontal, got, tc.want)
+		}
+	}
+}
+
+// boxRecorder records the widths and orientation of the boxes drawn.
+type boxRecorder struct {
+	*txtg.TextGraphics
+	boxes []string
+}
+
+func (r *boxRecorder) Boxes(boxes []chart.Box, width int, style chart.Style) {
+	for _, b := range boxes {
+		r.boxes = append(r.boxes, fmt.Sprintf("%d/%t", width, b.Horizontal))
+	}
+	r.TextGraphics.Boxes(boxes, width, style)
+}
+
+func TestBoxChartVarWidth(t *testing.T) {
+	c := chart.BoxChart{VarWidth: true, Horizontal: true}
+	c.Key.Hide = true
+	c.NextDataSet("", chart.Style{Symbol: 'o'})
+	for i, n := range []int{100, 25} {
+		data := make([]float64, n)
+		for j := range data {
+			data[j] = float64(j % 10)
+		}
+		c.AddSet(float64(i), data, true)
+	}
+	g := &boxRecorder{TextGraphics: txtg.New(60, 40)}
+	c.Plot(g)
+	// Widths are proportional to the square root of the number of samples.
+	if got, want := strings.Join(g.boxes, " "), "12/true 6/true"; got != want {
+		t.Errorf("got %q, want %q\n%s", got, want, g)
+	}
+}
@@ -3,6 +3,7 @@ package chart
 import (
 	"image/color"
 	"math"
+	"sort"
 )
 
 // HistChart represents histogram charts.
@@ -11,19 +12,26 @@ import (
 // Histograms are computed (binified) automatically from the raw
 // data.
 type HistChart struct {
-	XRange, YRange Range       // Lower limit of YRange is fixed to 0 and not available for input
+	XRange, YRange Range       // Lower limit of YRange is fixed to 0 (smallest non-empty bin if YRange.Log) and not available for input
 	Title          string      // Title of chart
 	Key            Key         // Key/Legend
 	Counts         bool        // Display counts instead of frequencies
+	Density        bool        // Display probability density (area of all bins is 1), overrides Counts
+	Cumulative     int         // 0: plain histogram, 1: cumulative ascending (samples up to bin), -1: descending (samples from bin on)
 	Stacked        bool        // Display different data sets ontop of each other
 	Shifted        bool        // Shift non-stacked bars sideways (and make them smaler)
 	FirstBin       float64     // center of the first (lowest bin)
-	BinWidth       float64     // Width of bins (0: auto)
+	BinWidth       float64     // Width of bins (0: auto, determined by Binning)
+	Binning        Binning     // how to determine the bins if BinWidth is 0
+	BinCount       int         // number of bins for QuantileBinning (0: Sturges' rule)
+	BinEdges       []float64   // explicit edges of (variable width) bins, overrides BinWidth and Binning
 	TBinWidth      TimeDelta   // BinWidth for time XRange
 	Gap            float64     // gap between bins in (bin-width units): 0<=Gap<1,
 	Sep            float64     // separation of bars in one bin (in bar width units) -1<Sep<1
 	Kernel         Kernel      // Smoothing kernel (usable only for non-stacked histograms)
+	Smoothing      KDE         // bandwidth and boundary correction for Kernel, AutoBandwidth uses the bin width
 	Options        PlotOptions // general stylistic optins
+	Annotations    Annotations // text, arrows, reference lines and spans
 	Data           []HistChartData
 }
 
@@ -32,8 +40,41 @@ type HistChartData struct {
 	Name    string
 	Style   Style
 	Samples []float64
+	Weights []float64 // weight of each sample (e.g. a count), nil or missing weights are 1
 }
 
+// weight returns the weight of sample i.
+func (d HistChartData) weight(i int) float64 {
+	if i >= len(d.Weights) {
+		return 1
+	}
+	return d.Weights[i]
+}
+
+// total returns the sum of the weights of all samples.
+func (d HistChartData) total() float64 {
+	if d.Weights == nil {
+		return float64(len(d.Samples))
+	}
+	t := 0.0
+	for i := range d.Samples {
+		t += d.weight(i)
+	}
+	return t
+}
+
+// Binning is a strategy to determine the bins of a histogram.
+type Binning int
+
+const (
+	TicBinning              Binning = iota // bin width derived from tic distance and sqrt(n), aligned to tics
+	SturgesBinning                         // log2(n)+1 bins
+	ScottBinning                           // bin width 3.49*sigma/n^(1/3)
+	FreedmanDiaconisBinning                // bin width 2*IQR/n^(1/3)
+	DoaneBinning                           // Sturges' rule corrected for skewness
+	QuantileBinning                        // variable width bins with equal count of samples
+)
+
 // Kernel is a smoothing kernel for histograms.
 type Kernel func(x float64) float64
 
@@ -100,7 +141,7 @@ func (c *HistChart) AddData(name string, data []float64, style Style) {
 	if len(c.Data) == 0 {
 		c.XRange.init()
 	}
-	c.Data = append(c.Data, HistChartData{name, style, data})
+	c.Data = append(c.Data, HistChartData{Name: name, Style: style, Samples: data})
 	for _, d := range data {
 		c.XRange.autoscale(d)
 	}
@@ -111,6 +152,31 @@ func (c *HistChart) AddData(name string, data []float64, style Style) {
 	}
 }
 
+// AddWeightedData adds data where each sample data[i] is weighted by
+// weights[i], e.g. pre-aggregated samples with weights being the counts.
+func (c *HistChart) AddWeightedData(name string, data, weights []float64, style Style) {
+	c.AddData(name, data, style)
+	c.Data[len(c.Data)-1].Weights = weights
+}
+
+// AddWeightedDataGeneric adds (value, weight) pairs: The X value of each
+// element of data is the sample and the Y value its weight.
+func (c *HistChart) AddWeightedDataGeneric(name string, data []XYValue, style Style) {
+	fdata, weights := make([]float64, len(data)), make([]float64, len(data))
+	for i, d := range data {
+		fdata[i], weights[i] = d.XVal(), d.YVal()
+	}
+	c.AddWeightedData(name, fdata, weights, style)
+}
+
+// AddDigest adds the samples summarized by the digest d: Each centroid is
+// added as one sample weighted by its number of samples. The histogram is
+// exact only for bins much wider than the centroids.
+func (c *HistChart) AddDigest(name string, d *TDigest, style Style) {
+	means, weights := d.Centroids()
+	c.AddWeightedData(name, means, weights, style)
+}
+
 // AddDataInt is a convenience method to add integer data (a simple wrapper
 // around AddData).
 func (c *HistChart) AddDataInt(name string, data []int, style Style) {
@@ -174,34 +240,59 @@ func (c *HistChart) widthFactor() (gf, sf float64) {
 	return
 }
 
-// Prepare binCnt bins of width binWidth starting from binStart and count
-// data samples per bin for each data set.  If c.Counts is true than the
-// absolute counts are returned instead if the frequencies.  max is the
+// Prepare the bins delimited by edges and count data samples (sum up their
+// weights) per bin for each data set. The last bin includes its upper edge.  If c.Counts is true than the
+// absolute counts are returned instead if the frequencies, if c.Density is
+// true the probability density is returned. Cumulative histograms are
+// summed up before scaling; the cumulative density is the fraction of the
+// samples, i.e. the empirical distribution function.  max is the
 // largest y-value which will occur in our plot.
-func (c *HistChart) binify(binStart, binWidth float64, binCnt int) (freqs [][]float64, max float64) {
-	x2bin := func(x float64) int { return int((x - binStart) / binWidth) }
+func (c *HistChart) binify(edges []float64) (freqs [][]float64, max float64) {
+	binCnt := len(edges) - 1
+	x2bin := func(x float64) int {
+		if x == edges[binCnt] {
+			return binCnt - 1
+		}
+		return sort.Search(len(edges), func(i int) bool { return edges[i] > x }) - 1
+	}
 
 	freqs = make([][]float64, len(c.Data)) // freqs[d][b] is frequency/count of bin b in dataset d
 	max = 0
 	for i, data := range c.Data {
 		freq := make([]float64, binCnt)
-		drops := 0
-		for _, x := range data.Samples {
+		drops := 0.0
+		for j, x := range data.Samples {
 			bin := x2bin(x)
 			if bin < 0 || bin >= binCnt {
 				// fmt.Printf("!!!!! Lost %.3f (bin=%d)\n", x, bin)
-				drops++
+				drops += data.weight(j)
 				continue
 			}
-			freq[bin] = freq[bin] + 1
+			freq[bin] = freq[bin] + data.weight(j)
 			//fmt.Printf("Value %.2f sorted into bin %d, count now %d\n", x, bin, int(freq[bin]))
 		}
+		switch {
+		case c.Cumulative > 0:
+			for bin := 1; bin < binCnt; bin++ {
+				freq[bin] += freq[bin-1]
+			}
+		case c.Cumulative < 0:
+			for bin := binCnt - 2; bin >= 0; bin-- {
+				freq[bin] += freq[bin+1]
+			}
+		}
 		// scale if requested and determine max
-		n := float64(len(data.Samples) - drops)
-		// DebugLogger.Printf("Dataset %d has %d samples (by %d drops).\n", i, int(n), drops)
+		n := data.total() - drops
+		// DebugLogger.Printf("Dataset %d has %.0f samples (by %.0f drops).\n", i, n, drops)
 		ff := 0.0
 		for bin := 0; bin < binCnt; bin++ {
-			if !c.Counts {
+			switch {
+			case n == 0:
+			case c.Density && c.Cumulative != 0:
+				freq[bin] /= n
+			case c.Density:
+				freq[bin] /= n * (edges[bin+1] - edges[bin])
+			case !c.Counts:
 				freq[bin] = 100 * freq[bin] / n
 			}
 			ff += freq[bin]
@@ -229,6 +320,138 @@ func (c *HistChart) binify(binStart, binWidth float64, binCnt int) (freqs [][]fl
 	return
 }
 
+// binEdges returns the edges of the bins: The explicit BinEdges, quantiles
+// of the samples or equal width bins. Bins of width BinWidth are aligned
+// to the tics for TicBinning and start at the smallest sample else.
+func (c *HistChart) binEdges() []float64 {
+	if len(c.BinEdges) > 1 {
+		edges := make([]float64, len(c.BinEdges))
+		copy(edges, c.BinEdges)
+		sort.Float64s(edges)
+		return uniqueEdges(edges)
+	}
+
+	if c.BinWidth == 0 {
+		switch c.Binning {
+		case QuantileBinning:
+			if edges := c.quantileEdges(); len(edges) > 1 {
+				return edges
+			}
+		case SturgesBinning, ScottBinning, FreedmanDiaconisBinning, DoaneBinning:
+			c.BinWidth = c.ruleBinWidth()
+		}
+		if c.BinWidth <= 0 || math.IsNaN(c.BinWidth) || math.IsInf(c.BinWidth, 0) {
+			c.BinWidth = 0
+			c.findBinWidth()
+		}
+	}
+
+	var binStart float64
+	var binCnt int
+	if c.Binning == TicBinning {
+		binStart = c.BinWidth * math.Ceil(c.XRange.Min/c.BinWidth)
+		binCnt = int(math.Floor(c.XRange.Max-binStart) / c.BinWidth)
+	} else {
+		binStart = c.XRange.DataMin
+		binCnt = int(math.Ceil((c.XRange.DataMax - binStart) / c.BinWidth))
+	}
+	binCnt = imax(1, binCnt)
+	edges := make([]float64, binCnt+1)
+	for i := range edges {
+		edges[i] = binStart + float64(i)*c.BinWidth
+	}
+	return edges
+}
+
+// samples returns the samples of all data sets inside the x range sorted
+// ascending together with their weights and the total weight n.
+func (c *HistChart) samples() (all, weights []float64, n float64) {
+	for _, data := range c.Data {
+		for j, x := range data.Samples {
+			if x >= c.XRange.DataMin && x <= c.XRange.DataMax {
+				all = append(all, x)
+				weights = append(weights, data.weight(j))
+				n += data.weight(j)
+			}
+		}
+	}
+	sort.Sort(weightedSamples{all, weights})
+	return all, weights, n
+}
+
+// weightedSamples sorts samples and their weights by sample value.
+type weightedSamples struct{ x, w []float64 }
+
+func (s weightedSamples) Len() int           { return len(s.x) }
+func (s weightedSamples) Less(i, j int) bool { return s.x[i] < s.x[j] }
+func (s weightedSamples) Swap(i, j int) {
+	s.x[i], s.x[j] = s.x[j], s.x[i]
+	s.w[i], s.w[j] = s.w[j], s.w[i]
+}
+
+// sturges returns the number of bins for n samples according to Sturges' rule.
+func sturges(n float64) float64 { return math.Log2(n) + 1 }
+
+// ruleBinWidth calculates the bin width according to the rule c.Binning.
+func (c *HistChart) ruleBinWidth() float64 {
+	all, weights, n := c.samples()
+	if len(all) < 2 || n < 2 {
+		return 0
+	}
+	span := all[len(all)-1] - all[0]
+	switch c.Binning {
+	case SturgesBinning:
+		return span / sturges(n)
+	case ScottBinning:
+		_, sd, _ := weightedMoments(all, weights)
+		return 3.49 * sd / math.Cbrt(n)
+	case FreedmanDiaconisBinning:
+		iqr := weightedQuantile(all, weights, 0.75) - weightedQuantile(all, weights, 0.25)
+		if iqr == 0 {
+			return span / sturges(n)
+		}
+		return 2 * iqr / math.Cbrt(n)
+	case DoaneBinning:
+		_, sd, skew := weightedMoments(all, weights)
+		if n < 3 || sd == 0 {
+			return span / sturges(n)
+		}
+		sg := math.Sqrt(6 * (n - 2) / ((n + 1) * (n + 3)))
+		return span / (sturges(n) + math.Log2(1+math.Abs(skew)/sg))
+	}
+	return 0
+}
+
+// quantileEdges returns the edges of c.BinCount (or by Sturges' rule)
+// bins each containing the same number (weight) of samples. Ties in the samples
+// may lead to fewer bins.
+func (c *HistChart) quantileEdges() []float64 {
+	all, weights, n := c.samples()
+	if len(all) < 2 || n <= 0 {
+		return nil
+	}
+	k := c.BinCount
+	if k <= 0 {
+		k = int(math.Ceil(sturges(math.Max(n, 1))))
+	}
+	edges := make([]float64, k+1)
+	for i := range edges {
+		edges[i] = weightedQuantile(all, weights, float64(i)/float64(k))
+	}
+	return uniqueEdges(edges)
+}
+
+// uniqueEdges removes duplicates from the sorted edges.
+func uniqueEdges(edges []float64) []float64 {
+	u := edges[:1]
+	for _, e := range edges[1:] {
+		if e > u[len(u)-1] {
+			u = append(u, e)
+		}
+	}
+	return u
+}
+
 func (c *HistChart) findBinWidth() {
 	bw := c.XRange.TicSetting.Delta
 	if bw == 0 { // this should not happen...
@@ -236,17 +459,17 @@ func (c *HistChart) findBinWidth() {
 	}
 
 	// Average sample count (n) and "optimum" bin count obc
-	n := 0
+	n := 0.0
 	for _, data := range c.Data {
-		for _, x := range data.Samples {
+		for j, x := range data.Samples {
 			// Count only data in valid x-range.
 			if x >= c.XRange.Min && x <= c.XRange.Max {
-				n++
+				n += data.weight(j)
 			}
 		}
 	}
-	n /= len(c.Data)
-	obc := math.Sqrt(float64(n))
+	n /= float64(len(c.Data))
+	obc := math.Sqrt(n)
 	// DebugLogger.Printf("Average size of %d data sets: %d (obc=%d)\n", len(c.Data), n, int(obc+0.5))
 
 	// Increase/decrease bin width if tic delta yields massively bad choice
@@ -272,10 +495,35 @@ func (c *HistChart) Reset() {
 
 // Plot will output the chart to the graphic device g.
 func (c *HistChart) Plot(g Graphics) {
-	layout := layout(g, c.Title, c.XRange.Label, c.YRange.Label,
-		c.XRange.TicSetting.Hide || c.XRange.TicSetting.HideLabels,
-		c.YRange.TicSetting.Hide || c.YRange.TicSetting.HideLabels,
-		&c.Key)
+	// The y axis is scaled after binning: Lay out the chart with an
+	// upper bound of the counts/frequencies instead.
+	yr := c.YRange
+	yr.DataMin, yr.DataMax = 0, 100
+	switch {
+	case c.Density && c.Cumulative != 0:
+		yr.DataMax = 1
+	case c.Density:
+		// Rough guess: Peak density is a few times the average density.
+		yr.DataMax = 4 / fmax(c.XRange.DataMax-c.XRange.DataMin, 1e-10)
+	case c.Counts:
+		yr.DataMax = 0
+		for _, data := range c.Data {
+			if c.Stacked {
+				yr.DataMax += data.total()
+			} else {
+				yr.DataMax = fmax(yr.DataMax, data.total())
+			}
+		}
+	}
+	if yr.Log {
+		yr.DataMin = yr.DataMax / 1000
+	}
+	if len(c.BinEdges) > 1 {
+		for _, e := range c.BinEdges {
+			c.XRange.autoscale(e)
+		}
+	}
+	layout := layout(g, c.Title, &c.XRange, &yr, &c.Key, c.Options)
 	fw, fh, _ := g.FontMetrics(elementStyle(c.Options, MajorAxisElement).Font)
 
 	width, height := layout.Width, layout.Height
@@ -289,24 +537,20 @@ func (c *HistChart) Plot(g Graphics) {
 
 	c.XRange.Setup(numxtics, numxtics+4, width, leftm, false)
 
-	// TODO(vodo) a) BinWidth might be input, alignment to tics should be nice, binCnt, ...
-	if c.BinWidth == 0 {
-		c.findBinWidth()
-	}
-
-	xmin, _ := c.XRange.Min, c.XRange.Max
-	binStart := c.BinWidth * math.Ceil(xmin/c.BinWidth)
-	c.FirstBin = binStart + c.BinWidth/2
-	binCnt := int(math.Floor(c.XRange.Max-binStart) / c.BinWidth)
-	// DebugLogger.Printf("Using %d bins from %.3f to %.3f width %.3f  (xrange: %.3f--%.3f)\n", binCnt, binStart, binStart+c.BinWidth*float64(binCnt), c.BinWidth, xmin, xmax)
-	counts, max := c.binify(binStart, c.BinWidth, binCnt)
+	edges := c.binEdges()
+	binCnt := len(edges) - 1
+	c.FirstBin = (edges[0] + edges[1]) / 2
+	// DebugLogger.Printf("Using %d bins from %.3f to %.3f  (xrange: %.3f--%.3f)\n", binCnt, edges[0], edges[binCnt], c.XRange.Min, c.XRange.Max)
+	counts, max := c.binify(edges)
+	meanWidth := (edges[binCnt] - edges[0]) / float64(binCnt)
 
 	// Calculate smoothed density plots and re-max y.
 	var smoothed [][]EPoint
-	if !c.Stacked && c.Kernel != nil {
+	smooth := !c.Stacked && c.Kernel != nil && c.Cumulative == 0
+	if smooth {
 		smoothed = make([][]EPoint, len(c.Data))
 		for d := range c.Data {
-			p, m := c.smoothed(d, binCnt)
+			p, m := c.smoothed(d, binCnt, meanWidth)
 			smoothed[d] = p
 			if m > max {
 				max = m
@@ -314,12 +558,33 @@ func (c *HistChart) Plot(g Graphics) {
 		}
 	}
 
-	// Fix lower end of y axis
-	c.YRange.DataMin = 0
-	c.YRange.MinMode.Fixed = true
-	c.YRange.MinMode.Value = 0
-	c.YRange.autoscale(float64(max))
+	// Fix lower end of y axis. Empty bins cannot be shown on a logarithmic
+	// axis: Scale to below the smallest non-empty bin (so that it is visible)
+	// and let bars start at the lower end of the axis.
+	if c.YRange.Log {
+		c.YRange.init()
+		for d := range counts {
+			for _, cnt := range counts[d] {
+				if cnt > 0 {
+					c.YRange.autoscale(cnt / 2)
+				}
+			}
+		}
+		c.YRange.autoscale(max)
+		if c.YRange.DataMin > c.YRange.DataMax { // no data at all
+			c.YRange.DataMin, c.YRange.DataMax = 1, 10
+		}
+	} else {
+		c.YRange.DataMin = 0
+		c.YRange.MinMode.Fixed = true
+		c.YRange.MinMode.Value = 0
+		c.YRange.autoscale(float64(max))
+	}
 	c.YRange.Setup(numytics, numytics+2, height, topm, true)
+	base := 0.0
+	if c.YRange.Log {
+		base = c.YRange.Min
+	}
 
 	g.Begin()
 
@@ -327,8 +592,10 @@ func (c *HistChart) Plot(g Graphics) {
 		drawTitle(g, c.Title, elementStyle(c.Options, TitleElement))
 	}
 
+	drawAnnotations(g, c.Annotations, BackgroundLayer, c.XRange, c.YRange, c.Options)
 	g.XAxis(c.XRange, topm+height+fh, topm, c.Options)
 	g.YAxis(c.YRange, leftm-int(2*fw), leftm+width, c.Options)
+	drawAnnotations(g, c.Annotations, BelowDataLayer, c.XRange, c.YRange, c.Options)
 
 	xf := c.XRange.Data2Screen
 	yf := c.YRange.Data2Screen
@@ -337,18 +604,21 @@ func (c *HistChart) Plot(g Graphics) {
 	n := float64(numSets)
 	gf, sf := c.widthFactor()
 
-	ww := c.BinWidth * (1 - gf) // w'
-	var w, s float64
-	if !c.Stacked && c.Shifted {
-		w = ww / (n + (n-1)*sf)
-		s = w * sf
-	} else {
-		w = ww
-		s = -ww
+	// Geometry of bin b: Center xb, width ww of all bars (w'), width w
+	// of one bar and separation s of bars.
+	geometry := func(b int) (xb, ww, w, s float64) {
+		xb = (edges[b] + edges[b+1]) / 2
+		ww = (edges[b+1] - edges[b]) * (1 - gf)
+		if !c.Stacked && c.Shifted {
+			w = ww / (n + (n-1)*sf)
+			s = w * sf
+		} else {
+			w = ww
+			s = -ww
+		}
+		return
 	}
 
-	// DebugLogger.Printf("gf=%.3f, sf=%.3f, bw=%.3f   ===>  ww=%.2f,   w=%.2f,  s=%.2f\n", gf, sf, c.BinWidth, ww, w, s)
-
 	if c.Shifted || c.Stacked {
 		for d := numSets - 1; d >= 0; d-- {
 			bars := make([]Barinfo, 0, binCnt)
@@ -357,7 +627,7 @@ func (c *HistChart) Plot(g Graphics) {
 				if counts[d][b] == 0 {
 					continue
 				}
-				xb := binStart + (float64(b)+0.5)*c.BinWidth
+				xb, ww, w, s := geometry(b)
 				x := xb - ww/2 + float64(d)*(s+w)
 				xs := xf(x)
 				xss := xf(x + w)
@@ -370,7 +640,10 @@ func (c *HistChart) Plot(g Graphics) {
 						off += counts[dd][b]
 					}
 				}
-				a, aa := yf(float64(off+counts[d][b])), yf(float64(off))
+				if off+counts[d][b] <= base {
+					continue
+				}
+				a, aa := yf(float64(off+counts[d][b])), yf(fmax(off, base))
 				thebar.y, thebar.h = a, iabs(a-aa)
 				bars = append(bars, thebar)
 			}
@@ -406,16 +679,16 @@ func (c *HistChart) Plot(g Graphics) {
 				}
 			}
 			for d := 0; d < numSets; d++ {
-				if counts[order[d]][b] == 0 {
+				if counts[order[d]][b] <= base {
 					continue
 				}
-				xb := binStart + (float64(b)+0.5)*c.BinWidth
+				xb, ww, w, s := geometry(b)
 				x := xb - ww/2 + float64(d)*(s+w)
 				xs := xf(x)
 				xss := xf(x + w)
 				thebar := Barinfo{x: xs, w: xss - xs}
 
-				a, aa := yf(float64(counts[order[d]][b])), yf(0)
+				a, aa := yf(float64(counts[order[d]][b])), yf(base)
 				thebar.y, thebar.h = a, iabs(a-aa)
 				bars[0] = thebar
 				g.Bars(bars, c.Data[order[d]].Style)
@@ -423,18 +696,20 @@ func (c *HistChart) Plot(g Graphics) {
 		}
 	}
 
-	if !c.Stacked && c.Kernel != nil {
+	if smooth {
 		for d := numSets - 1; d >= 0; d-- {
 			style := Style{Symbol: /*c.Data[d].Style.Symbol*/ 'X', LineColor: c.Data[d].Style.LineColor,
 				LineWidth: 1, LineStyle: SolidLine}
 			for j := range smoothed[d] {
 				// now YRange is set up: transform to screen coordinates
-				smoothed[d][j].Y = float64(c.YRange.Data2Screen(smoothed[d][j].Y))
+				smoothed[d][j].Y = float64(c.YRange.Data2Screen(fmax(smoothed[d][j].Y, base)))
 			}
 			g.Scatter(smoothed[d], PlotStyleLines, style)
 		}
 	}
 
+	drawAnnotations(g, c.Annotations, AboveDataLayer, c.XRange, c.YRange, c.Options)
+
 	if !c.Key.Hide {
 		g.Key(layout.KeyX, layout.KeyY, c.Key, c.Options)
 	}
@@ -443,30 +718,37 @@ func (c *HistChart) Plot(g Graphics) {
 
 // Smooth data set i. The Y-value of the returned points is not jet in screen coordinates
 // but in data coordinates! (Reason: YRange not set up jet)
-func (c *HistChart) smoothed(i, binCnt int) (points []EPoint, max float64) {
+func (c *HistChart) smoothed(i, binCnt int, binWidth float64) (points []EPoint, max float64) {
 	nan := math.NaN()
 
 	samples := imax(25, binCnt*5)
 
 	step := (c.XRange.Max - c.XRange.Min) / float64(samples)
 	points = make([]EPoint, 0, 50)
-	h := c.BinWidth
-	K := c.Kernel
-	n := float64(len(c.Data[i].Samples))
+	data := c.Data[i]
+	n := data.total()
+	if n == 0 {
+		return
+	}
+	kde := c.Smoothing
+	kde.Kernel = c.Kernel
+	if kde.H == 0 && kde.Rule == AutoBandwidth {
+		kde.H = binWidth
+	}
+	h := kde.Bandwidth(data.Samples, data.Weights)
 
 	for x := c.XRange.Min; x <= c.XRange.Max; x += step {
-		f := 0.0
-		for _, xi := range c.Data[i].Samples {
-			f += K((x - xi) / h)
-		}
-		f /= h
-		if !c.Counts {
-			f /= n
-			f *= 100 // as display is in %
-		}
+		f := kde.density(data.Samples, data.Weights, n, h, x)
+		if !c.Density {
+			if c.Counts {
+				f *= n
+			} else {
+				f *= 100 // as display is in %
+			}
 
-		// Rescale kernel density estimation by width of bars:
-		f *= c.BinWidth
+			// Rescale kernel density estimation by width of bars:
+			f *= binWidth
+		}
 		if f > max {
 			max = f
 		}
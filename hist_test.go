@@ -0,0 +1,72 @@
+package chart
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHistBinify(t *testing.T) {
+	samples := []float64{0.5, 1.5, 1.5, 2.5, 3.5, 3.5, 3.5, 3.5}
+	unit := []float64{0, 1, 2, 3, 4}
+	for _, tc := range []struct {
+		counts, density bool
+		cumulative      int
+		edges           []float64
+		expected        string
+	}{
+		{true, false, 0, unit, "[1 2 1 4] 4"},
+		{false, false, 0, unit, "[12.5 25 12.5 50] 50"},
+		{true, false, 1, unit, "[1 3 4 8] 8"},
+		{true, false, -1, unit, "[8 7 5 4] 8"},
+		{false, true, 0, unit, "[0.125 0.25 0.125 0.5] 0.5"},
+		{false, true, 1, unit, "[0.125 0.375 0.5 1] 1"},
+		{false, true, 0, []float64{0, 2, 4}, "[0.1875 0.3125] 0.3125"},
+		{false, true, 0, []float64{0, 1, 4}, "[0.125 0.2916666666666667] 0.2916666666666667"},
+		{true, false, 0, []float64{0, 1, 4}, "[1 7] 7"},
+	} {
+		c := HistChart{Counts: tc.counts, Density: tc.density, Cumulative: tc.cumulative}
+		c.AddData("", samples, Style{})
+		freqs, max := c.binify(tc.edges)
+		if got := fmt.Sprintf("%v %g", freqs[0], max); got != tc.expected {
+			t.Errorf("Counts=%t Density=%t Cumulative=%d: Got %q, expected %q",
+				tc.counts, tc.density, tc.cumulative, got, tc.expected)
+		}
+	}
+}
+
+func TestHistBinEdges(t *testing.T) {
+	samples := []float64{0.5, 1.5, 1.5, 2.5, 3.5, 3.5, 3.5, 3.5}
+	for _, tc := range []struct {
+		c        HistChart
+		expected string
+	}{
+		{HistChart{Binning: SturgesBinning}, "[0.5 1.25 2 2.75 3.5]"},
+		{HistChart{Binning: SturgesBinning, BinWidth: 1.5}, "[0.5 2 3.5]"},
+		{HistChart{Binning: QuantileBinning, BinCount: 2}, "[0.5 3 3.5]"},
+		{HistChart{Binning: QuantileBinning, BinCount: 4}, "[0.5 1.5 3 3.5]"},
+		{HistChart{BinEdges: []float64{2, 0, 2, 4}}, "[0 2 4]"},
+	} {
+		c := tc.c
+		c.AddData("", samples, Style{})
+		if got := fmt.Sprintf("%v", c.binEdges()); got != tc.expected {
+			t.Errorf("Binning=%d: Got %q, expected %q", tc.c.Binning, got, tc.expected)
+		}
+	}
+}
+
+func TestHistWeighted(t *testing.T) {
+	expanded := HistChart{Binning: QuantileBinning, BinCount: 4}
+	expanded.AddData("", []float64{0.5, 1.5, 1.5, 2.5, 3.5, 3.5, 3.5, 3.5}, Style{})
+	weighted := HistChart{Binning: QuantileBinning, BinCount: 4}
+	weighted.AddWeightedData("", []float64{3.5, 0.5, 1.5, 2.5}, []float64{4, 1, 2, 1}, Style{})
+
+	for _, c := range []*HistChart{&expanded, &weighted} {
+		if got := fmt.Sprintf("%v", c.binEdges()); got != "[0.5 1.5 3 3.5]" {
+			t.Errorf("Got edges %q", got)
+		}
+		freqs, max := c.binify([]float64{0, 1, 2, 3, 4})
+		if got := fmt.Sprintf("%v %g", freqs[0], max); got != "[12.5 25 12.5 50] 50" {
+			t.Errorf("Got frequencies %q", got)
+		}
+	}
+}
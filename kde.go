@@ -0,0 +1,250 @@
+package chart
+
+import (
+	"math"
+	"sort"
+)
+
+// BandwidthRule selects the bandwidth of a kernel density estimate.
+type BandwidthRule int
+
+const (
+	AutoBandwidth            BandwidthRule = iota // Silverman's rule for KDE, the bin width for HistChart
+	SilvermanBandwidth                            // 0.9 * min(sigma, IQR/1.34) / n^(1/5)
+	ScottBandwidth                                // 1.06 * sigma / n^(1/5)
+	CrossValidationBandwidth                      // minimize least squares cross-validation score
+)
+
+// KDE describes a kernel density estimation: The kernel, the bandwidth
+// (or the rule to select it) and the support of the data. If the data are
+// bounded (e.g. durations are never negative) the density is corrected at
+// the bounds by reflecting the samples at Lower and/or Upper.
+//
+// The bandwidth rules yield the bandwidth of a kernel with unit variance;
+// it is scaled by the standard deviation of Kernel so that all kernels
+// smooth comparably.
+type KDE struct {
+	Kernel       Kernel        // smoothing kernel, nil is GaussKernel
+	Rule         BandwidthRule // how to select the bandwidth if H is 0
+	H            float64       // bandwidth, the kernel is evaluated at (x-sample)/H
+	Lower, Upper float64       // bounds of the data
+	BoundedBelow bool          // samples are >= Lower: correct density at Lower
+	BoundedAbove bool          // samples are <= Upper: correct density at Upper
+}
+
+func (k KDE) kernel() Kernel {
+	if k.Kernel == nil {
+		return GaussKernel
+	}
+	return k.Kernel
+}
+
+// kernelSD returns the standard deviation of the kernel K.
+func kernelSD(K Kernel) float64 {
+	const steps, width = 2400, 6.0
+	var m0, m2 float64
+	for i := 0; i < steps; i++ {
+		x := -width + (float64(i)+0.5)*2*width/steps
+		m0 += K(x)
+		m2 += x * x * K(x)
+	}
+	if m0 == 0 {
+		return 1
+	}
+	return math.Sqrt(m2 / m0)
+}
+
+// Bandwidth returns the bandwidth used for samples (with weights which may
+// be nil): Either H or the bandwidth selected by Rule.
+func (k KDE) Bandwidth(samples, weights []float64) float64 {
+	if k.H > 0 {
+		return k.H
+	}
+	sorted, w := make([]float64, len(samples)), make([]float64, len(samples))
+	copy(sorted, samples)
+	for i := range w {
+		w[i] = 1
+		if i < len(weights) {
+			w[i] = weights[i]
+		}
+	}
+	sort.Sort(weightedSamples{sorted, w})
+
+	var h float64
+	switch k.Rule {
+	case ScottBandwidth:
+		h = scottBandwidth(sorted, w)
+	case CrossValidationBandwidth:
+		h = k.crossValidation(sorted, w)
+	default:
+		h = silvermanBandwidth(sorted, w)
+	}
+	if h <= 0 || math.IsNaN(h) || math.IsInf(h, 0) {
+		// Degenerated data: Use a small fraction of the magnitude.
+		h = 1
+		if len(sorted) > 0 && sorted[0] != 0 {
+			h = math.Abs(sorted[0]) / 10
+		}
+		return h
+	}
+	return h / kernelSD(k.kernel())
+}
+
+// silvermanBandwidth implements Silverman's rule of thumb for sorted data.
+func silvermanBandwidth(data, weights []float64) float64 {
+	_, sd, _ := weightedMoments(data, weights)
+	iqr := weightedQuantile(data, weights, 0.75) - weightedQuantile(data, weights, 0.25)
+	s := sd
+	if iqr > 0 && iqr/1.34 < s {
+		s = iqr / 1.34
+	}
+	return 0.9 * s * math.Pow(sum(weights), -0.2)
+}
+
+// scottBandwidth implements Scott's rule of thumb for sorted data.
+func scottBandwidth(data, weights []float64) float64 {
+	_, sd, _ := weightedMoments(data, weights)
+	return 1.06 * sd * math.Pow(sum(weights), -0.2)
+}
+
+// sum returns the sum of data.
+func sum(data []float64) float64 {
+	s := 0.0
+	for _, v := range data {
+		s += v
+	}
+	return s
+}
+
+// crossValidation selects the (unit variance kernel) bandwidth which
+// minimizes the least squares cross-validation score integral(f^2) -
+// 2/n*sum(f_{-i}(x_i)) where f_{-i} is the estimate without sample i.
+// Weights are treated as counts of identical samples. Candidates are
+// searched on a logarithmic grid around Silverman's bandwidth. The cost
+// is quadratic in the number of (distinct) samples.
+func (k KDE) crossValidation(data, weights []float64) float64 {
+	h0 := silvermanBandwidth(data, weights)
+	n := sum(weights)
+	if h0 <= 0 || n < 2 || math.IsNaN(h0) {
+		return h0
+	}
+	sd := kernelSD(k.kernel())
+	K := k.kernel()
+	best, bestScore := h0, math.Inf(1)
+	const candidates = 40
+	for c := 0; c < candidates; c++ {
+		h := h0 * math.Pow(10, -1+1.3*float64(c)/(candidates-1)) // h0/10 ... 2*h0
+		kh := h / sd
+
+		// Integral of f^2 by the midpoint rule.
+		lo, hi := data[0]-4*h, data[len(data)-1]+4*h
+		if k.BoundedBelow {
+			lo = math.Max(lo, k.Lower)
+		}
+		if k.BoundedAbove {
+			hi = math.Min(hi, k.Upper)
+		}
+		const steps = 256
+		dx := (hi - lo) / steps
+		integral := 0.0
+		for i := 0; i < steps; i++ {
+			f := k.density(data, weights, n, kh, lo+(float64(i)+0.5)*dx)
+			integral += f * f * dx
+		}
+
+		// Leave-one-out estimates at the samples.
+		loo := 0.0
+		for i, xi := range data {
+			f := k.density(data, weights, n, kh, xi) * n
+			f -= K(0) / kh // remove one observation of xi
+			loo += weights[i] * f / (n - 1)
+		}
+
+		if score := integral - 2*loo/n; score < bestScore {
+			best, bestScore = h, score
+		}
+	}
+	return best
+}
+
+// density evaluates the estimate at x for samples with weights of total
+// weight n and bandwidth h.
+func (k KDE) density(samples, weights []float64, n, h, x float64) float64 {
+	if (k.BoundedBelow && x < k.Lower) || (k.BoundedAbove && x > k.Upper) {
+		return 0
+	}
+	K := k.kernel()
+	f := 0.0
+	for i, xi := range samples {
+		w := 1.0
+		if i < len(weights) {
+			w = weights[i]
+		}
+		v := K((x - xi) / h)
+		if k.BoundedBelow {
+			v += K((x - (2*k.Lower - xi)) / h)
+		}
+		if k.BoundedAbove {
+			v += K((x - (2*k.Upper - xi)) / h)
+		}
+		f += w * v
+	}
+	return f / (n * h)
+}
+
+// Density returns the estimated probability density of samples (with
+// weights which may be nil) at x.
+func (k KDE) Density(samples, weights []float64, x float64) float64 {
+	n := 0.0
+	for i := range samples {
+		if i < len(weights) {
+			n += weights[i]
+		} else {
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return k.density(samples, weights, n, k.Bandwidth(samples, weights), x)
+}
+
+// Curve returns the estimated probability density of samples (with weights
+// which may be nil) at n equidistant points from min to max. If min >= max
+// the curve extends three bandwidths beyond the samples (but not beyond
+// the bounds). The points can be added to a ScatterChart with AddData.
+func (k KDE) Curve(samples, weights []float64, min, max float64, n int) []EPoint {
+	if len(samples) == 0 {
+		return nil
+	}
+	k.H = k.Bandwidth(samples, weights)
+	if min >= max {
+		min, max = math.Inf(1), math.Inf(-1)
+		for _, x := range samples {
+			min, max = fmin(min, x), fmax(max, x)
+		}
+		min, max = min-3*k.H, max+3*k.H
+		if k.BoundedBelow {
+			min = fmax(min, k.Lower)
+		}
+		if k.BoundedAbove {
+			max = fmin(max, k.Upper)
+		}
+	}
+	if n < 2 {
+		n = 100
+	}
+	nan := math.NaN()
+	points := make([]EPoint, n)
+	for i := range points {
+		x := min + (max-min)*float64(i)/float64(n-1)
+		points[i] = EPoint{X: x, Y: k.Density(samples, weights, x), DeltaX: nan, DeltaY: nan}
+	}
+	return points
+}
+
+// DensityCurve is a convenience function which returns a Gaussian kernel
+// density estimate of samples with Silverman's bandwidth at n points.
+func DensityCurve(samples []float64, n int) []EPoint {
+	return KDE{}.Curve(samples, nil, 0, 0, n)
+}
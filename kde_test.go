@@ -0,0 +1,53 @@
+package chart
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKDEBandwidth(t *testing.T) {
+	samples := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	// sigma = 3.03, IQR/1.34 = 4.5/1.34 = 3.36
+	if h, expected := (KDE{}).Bandwidth(samples, nil), 0.9*3.0277*math.Pow(10, -0.2); math.Abs(h-expected) > 1e-3 {
+		t.Errorf("Silverman: Got %.4f, expected %.4f", h, expected)
+	}
+	if h, expected := (KDE{Rule: ScottBandwidth}).Bandwidth(samples, nil), 1.06*3.0277*math.Pow(10, -0.2); math.Abs(h-expected) > 1e-3 {
+		t.Errorf("Scott: Got %.4f, expected %.4f", h, expected)
+	}
+	// Kernels of different width smooth comparably.
+	hg := (KDE{}).Bandwidth(samples, nil)
+	he := (KDE{Kernel: EpanechnikovKernel}).Bandwidth(samples, nil)
+	if r := he / hg; math.Abs(r-math.Sqrt(5)) > 1e-2 {
+		t.Errorf("Epanechnikov/Gauss: Got ratio %.4f, expected %.4f", r, math.Sqrt(5))
+	}
+	if h := (KDE{Rule: CrossValidationBandwidth}).Bandwidth(samples, nil); h < hg/10 || h > 2*hg {
+		t.Errorf("Cross-validation: Got %.4f out of range", h)
+	}
+	// Weights act like repeated samples.
+	hw := (KDE{}).Bandwidth([]float64{1, 2, 3}, []float64{2, 1, 2})
+	hr := (KDE{}).Bandwidth([]float64{1, 1, 2, 3, 3}, nil)
+	if math.Abs(hw-hr) > 1e-9 {
+		t.Errorf("Weighted: Got %.4f, expected %.4f", hw, hr)
+	}
+}
+
+func TestKDEIntegral(t *testing.T) {
+	samples := []float64{0.1, 0.2, 0.3, 0.5, 0.8, 1.3, 2.1, 3.4}
+	for _, kde := range []KDE{
+		{},
+		{Kernel: EpanechnikovKernel, Rule: ScottBandwidth},
+		{Lower: 0, BoundedBelow: true},
+	} {
+		points := kde.Curve(samples, nil, -5, 10, 1501)
+		integral := 0.0
+		for _, p := range points {
+			integral += p.Y * 0.01
+		}
+		if math.Abs(integral-1) > 0.01 {
+			t.Errorf("%+v: Got integral %.4f", kde, integral)
+		}
+		if kde.BoundedBelow && kde.Density(samples, nil, -0.01) != 0 {
+			t.Errorf("Density below bound")
+		}
+	}
+}
@@ -2,9 +2,11 @@ package chart
 
 import (
 	"fmt"
+	"image/color"
 	"math"
+	"sort"
+	"strings"
 	//	"os"
-	// "strings"
 )
 
 // PieChart represents pie and ring charts.
@@ -13,6 +15,12 @@ import (
 // The FmtVal and FmtKey function are used to format optional labels
 // on the pie segments (FmtVal) and on the legend/key entries if non
 // nil. The FmtKey must be set before adding data via the AddXY methods.
+// The same holds for the fields controlling sorting of segments and
+// merging of small segments into one "Other" segment.
+// Use the Value or Share method of a NumberFormat for localized labels.
+// Labels of the outermost ring can be placed outside the pie (see LabelPos)
+// where they are connected to their segment by leader lines.
+// Ring charts may show a text like a KPI in their center (see Center).
 type PieChart struct {
 	Title   string  // The title
 	Key     Key     // The Key/Legend
@@ -20,10 +28,31 @@ type PieChart struct {
 	Options PlotOptions
 	Data    []CategoryChartData // The data
 
-	FmtVal func(value, sume float64) string // add value labels to pie segments
-	FmtKey func(value, sume float64) string // add value labels to key entries
+	FmtVal    func(value, sume float64) string // add value labels to pie segments
+	FmtKey    func(value, sume float64) string // add value labels to key entries
+	CatLabels bool                             // label pie segments with category name (followed by FmtVal)
+	LabelPos  PieLabelPos                      // placement of segment labels
+
+	Center     string // text in the hole of ring charts (Inner > 0), lines are separated by "\n"
+	CenterFont Font   // font of Center, zero value uses HugeFontSize
+
+	Sort        bool    // sort segments by decreasing value
+	MinValue    float64 // merge segments with value below MinValue into Other segment
+	MinFraction float64 // merge segments with less than MinFraction of the total into Other segment
+	TopN        int     // if > 0: keep only the TopN largest segments and merge the rest into Other segment
+	OtherName   string  // category name of merged segment, "" is "Other"
+	OtherStyle  Style   // style of merged segment, empty style uses gray
 }
 
+// PieLabelPos determines where the labels of pie segments are placed.
+type PieLabelPos int
+
+const (
+	InsideLabels  PieLabelPos = iota // labels inside the segments
+	OutsideLabels                    // labels around the pie with leader lines
+	AutoLabels                       // outside only if label does not fit into its segment
+)
+
 // IntegerValue will format value (ignoring sum) as an integer.
 // It is a convenience function which can be assigned to the
 // PieChart.FmtVal or PieChart.FmtKey field.
@@ -66,15 +95,19 @@ type CategoryChartData struct {
 	Samples []CatValue
 }
 
+// AddData adds the data set data under name to the chart. Missing styles are
+// generated by AutoStyle. Segments are sorted and merged according to the
+// settings of c.
 func (c *PieChart) AddData(name string, data []CatValue, style []Style) {
 	if len(style) < len(data) {
 		ns := make([]Style, len(data))
-		copy(style, ns)
+		copy(ns, style)
 		for i := len(style); i < len(data); i++ {
 			ns[i] = AutoStyle(i-len(style), true)
 		}
 		style = ns
 	}
+	data, style = c.aggregate(data, style)
 	c.Data = append(c.Data, CategoryChartData{name, style, data})
 	c.Key.Entries = append(c.Key.Entries, KeyEntry{PlotStyle: -1, Text: name})
 	var sum float64
@@ -93,6 +126,89 @@ func (c *PieChart) AddData(name string, data []CatValue, style []Style) {
 	}
 }
 
+// pieSegments sorts data and style by decreasing value.
+type pieSegments struct {
+	data  []CatValue
+	style []Style
+}
+
+func (p pieSegments) Len() int           { return len(p.data) }
+func (p pieSegments) Less(i, j int) bool { return p.data[i].Val > p.data[j].Val }
+func (p pieSegments) Swap(i, j int) {
+	p.data[i], p.data[j] = p.data[j], p.data[i]
+	p.style[i], p.style[j] = p.style[j], p.style[i]
+}
+
+// aggregate returns copies of data and style sorted (if c.Sort) and with
+// all segments below the thresholds of c merged into one Other segment
+// which is appended last.
+func (c *PieChart) aggregate(data []CatValue, style []Style) ([]CatValue, []Style) {
+	data = append([]CatValue(nil), data...)
+	style = append([]Style(nil), style[:len(data)]...)
+	if c.Sort {
+		sort.Stable(pieSegments{data, style})
+	}
+	if c.MinValue <= 0 && c.MinFraction <= 0 && c.TopN <= 0 {
+		return data, style
+	}
+
+	var sum float64
+	for _, d := range data {
+		sum += d.Val
+	}
+	// Values of the TopN largest segments are at least topMin; ties
+	// segments of value topMin are kept (in order).
+	topMin, ties := math.Inf(-1), len(data)
+	if c.TopN > 0 && c.TopN < len(data) {
+		sorted := make([]float64, len(data))
+		for i, d := range data {
+			sorted[i] = d.Val
+		}
+		sort.Float64s(sorted)
+		topMin, ties = sorted[len(sorted)-c.TopN], 0
+		for _, v := range sorted[len(sorted)-c.TopN:] {
+			if v == topMin {
+				ties++
+			}
+		}
+	}
+
+	other := CatValue{Cat: c.OtherName}
+	if other.Cat == "" {
+		other.Cat = "Other"
+	}
+	kept, merged := 0, 0
+	for i, d := range data {
+		small := d.Val < c.MinValue || d.Val < c.MinFraction*sum || d.Val < topMin
+		if d.Val == topMin {
+			if ties == 0 {
+				small = true
+			}
+			ties--
+		}
+		if small {
+			other.Val += d.Val
+			other.Flag = other.Flag || d.Flag
+			merged++
+			continue
+		}
+		data[kept], style[kept] = data[i], style[i]
+		kept++
+	}
+	data, style = data[:kept], style[:kept]
+	if merged == 0 {
+		return data, style
+	}
+
+	ostyle := c.OtherStyle
+	if ostyle.empty() {
+		ostyle = Style{Symbol: '.', SymbolColor: color.NRGBA{0x80, 0x80, 0x80, 0xff}, SymbolSize: 1,
+			LineColor: color.NRGBA{0x80, 0x80, 0x80, 0xff}, LineWidth: 3, LineStyle: SolidLine,
+			FillColor: color.NRGBA{0xc0, 0xc0, 0xc0, 0xff}}
+	}
+	return append(data, other), append(style, ostyle)
+}
+
 func (c *PieChart) AddDataPair(name string, cat []string, val []float64) {
 	n := imin(len(cat), len(val))
 	data := make([]CatValue, n)
@@ -119,7 +235,7 @@ func (c *PieChart) Reset() {}
 
 // Plot outputs the scatter chart sc to g.
 func (c *PieChart) Plot(g Graphics) {
-	layout := layout(g, c.Title, "", "", true, true, &c.Key)
+	layout := layout(g, c.Title, nil, nil, &c.Key, c.Options)
 
 	width, height := layout.Width, layout.Height
 	topm, leftm := layout.Top, layout.Left
@@ -128,6 +244,27 @@ func (c *PieChart) Plot(g Graphics) {
 	r := imin(height, width) / 2
 	x0, y0 := leftm+r, topm+r
 
+	// Make room for outside labels: Drivers which stretch circles also
+	// shift the pie to the right by the additional width.
+	stretch := circleStretch(g)
+	labels, out := c.labels(g, r)
+	outside := false
+	for _, o := range out {
+		outside = outside || o
+	}
+	if outside {
+		fw, fh, _ := g.FontMetrics(Font{})
+		lw := 0
+		for _, t := range labels[0] {
+			lw = imax(lw, g.TextLen(t, Font{}))
+		}
+		lead := PieLabelLead(fh) + int(fw)
+		r = imin(int(float64(width/2-lw-lead)/stretch), height/2-fh)
+		r = imax(r, 2*fh)
+		x0, y0 = leftm+width/2, topm+height/2
+		x0 -= int(float64(r) * (stretch - 1))
+	}
+
 	// Make sure pie fits into plotting area
 	rshift := int(float64(r) * PieChartHighlight)
 	if rshift < 6 {
@@ -147,7 +284,8 @@ func (c *PieChart) Plot(g Graphics) {
 		drawTitle(g, c.Title, elementStyle(c.Options, TitleElement))
 	}
 
-	for _, data := range c.Data {
+	rout, inner := r, 0 // radius of outermost ring, inner radius of innermost ring
+	for i, data := range c.Data {
 		var sum float64
 		for _, d := range data.Samples {
 			sum += d.Val
@@ -165,27 +303,92 @@ func (c *PieChart) Plot(g Graphics) {
 			alpha := 2 * math.Pi * d.Val / sum
 			shift := 0
 
-			var t string
-			if c.FmtVal != nil {
-				t = c.FmtVal(d.Val, sum)
+			t, tp := labels[i][j], "c"
+			if i == 0 && out[j] {
+				tp = "o"
 			}
 			if d.Flag {
 				shift = rshift
 			}
 
-			wedges[j] = Wedgeinfo{Phi: phi, Psi: phi + alpha, Text: t, Tp: "c",
+			wedges[j] = Wedgeinfo{Phi: phi, Psi: phi + alpha, Text: t, Tp: tp,
 				Style: style, Font: Font{}, Shift: shift}
 
 			phi += alpha
 		}
 		g.Rings(wedges, x0, y0, r, ri)
 
+		inner = ri
 		r = int(float64(r) * PieChartShrinkage)
 	}
 
+	if c.Center != "" && inner > 0 {
+		font := c.CenterFont
+		if font.Name == "" && font.Size == 0 && font.Color == nil {
+			font.Size = HugeFontSize
+		}
+		cx := x0 + int(float64(rout)*(stretch-1)) // center of the shifted pie
+		drawCenterText(g, cx, y0, c.Center, font)
+	}
+
 	if !c.Key.Hide {
 		g.Key(layout.KeyX, layout.KeyY, c.Key, c.Options)
 	}
 
 	g.End()
 }
+
+// drawCenterText draws the (multi line) text t centered at (x,y).
+func drawCenterText(g Graphics, x, y int, t string, font Font) {
+	lines := strings.Split(t, "\n")
+	_, fh, _ := g.FontMetrics(font)
+	y -= (len(lines) - 1) * fh / 2
+	for i, line := range lines {
+		g.Text(x, y+i*fh, line, "cc", 0, font)
+	}
+}
+
+// labels returns the labels of all segments and which labels of the
+// outermost ring are placed outside if the pie has radius r.
+func (c *PieChart) labels(g Graphics, r int) (labels [][]string, out []bool) {
+	labels = make([][]string, len(c.Data))
+	for i, data := range c.Data {
+		var sum float64
+		for _, d := range data.Samples {
+			sum += d.Val
+		}
+		labels[i] = make([]string, len(data.Samples))
+		for j, d := range data.Samples {
+			var t string
+			if c.CatLabels {
+				t = d.Cat
+			}
+			if c.FmtVal != nil {
+				if t != "" {
+					t += " "
+				}
+				t += c.FmtVal(d.Val, sum)
+			}
+			labels[i][j] = t
+			if i == 0 {
+				out = append(out, c.outside(g, t, 2*math.Pi*d.Val/sum, r))
+			}
+		}
+	}
+	return labels, out
+}
+
+// outside reports whether label t of a segment of the outermost ring
+// spanning the angle alpha is drawn outside the pie of radius r.
+func (c *PieChart) outside(g Graphics, t string, alpha float64, r int) bool {
+	switch {
+	case t == "" || c.LabelPos == InsideLabels:
+		return false
+	case c.LabelPos == OutsideLabels:
+		return true
+	}
+	// Labels are drawn at about 2/3 of the radius, see GenericRings.
+	_, fh, _ := g.FontMetrics(Font{})
+	arc := alpha * float64(2*r) / 3
+	return arc < float64(g.TextLen(t, Font{})) || arc < float64(2*fh)
+}
@@ -0,0 +1,112 @@
+package chart
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPieAggregate(t *testing.T) {
+	data := []CatValue{{"A", 5, false}, {"B", 40, false}, {"C", 1, false}, {"D", 20, false},
+		{"E", 5, false}, {"F", 2, false}, {"G", 27, false}}
+	samples := []struct {
+		chart    PieChart
+		expected string
+	}{
+		{PieChart{}, "A:5 B:40 C:1 D:20 E:5 F:2 G:27 "},
+		{PieChart{Sort: true}, "B:40 G:27 D:20 A:5 E:5 F:2 C:1 "},
+		{PieChart{MinValue: 5}, "A:5 B:40 D:20 E:5 G:27 Other:3 "},
+		{PieChart{MinFraction: 0.1, OtherName: "Rest"}, "B:40 D:20 G:27 Rest:13 "},
+		{PieChart{TopN: 3, Sort: true}, "B:40 G:27 D:20 Other:13 "},
+		{PieChart{TopN: 4}, "A:5 B:40 D:20 G:27 Other:8 "},
+		{PieChart{TopN: 10}, "A:5 B:40 C:1 D:20 E:5 F:2 G:27 "},
+	}
+
+	for i, s := range samples {
+		style := make([]Style, len(data))
+		for j := range style {
+			style[j] = AutoStyle(j, true)
+		}
+		d, st := s.chart.aggregate(data, style)
+		if len(d) != len(st) {
+			t.Errorf("%d: got %d values but %d styles", i, len(d), len(st))
+		}
+		got := ""
+		for _, cv := range d {
+			got += fmt.Sprintf("%s:%g ", cv.Cat, cv.Val)
+		}
+		if got != s.expected {
+			t.Errorf("%d: got %q, expected %q", i, got, s.expected)
+		}
+	}
+}
+
+// textRecorder is a MinimalGraphics which records the texts drawn.
+type textRecorder struct {
+	texts []string
+}
+
+func (r *textRecorder) Background() (uint8, uint8, uint8, uint8) { return 255, 255, 255, 255 }
+func (r *textRecorder) FontMetrics(Font) (float32, int, bool)    { return 6, 10, false }
+func (r *textRecorder) TextLen(t string, f Font) int             { return 6 * len(t) }
+func (r *textRecorder) Line(x0, y0, x1, y1 int, style Style)     {}
+func (r *textRecorder) Text(x, y int, t, align string, rot int, f Font) {
+	r.texts = append(r.texts, fmt.Sprintf("%s@%d,%d%s", t, x, y, align))
+}
+
+func TestPieLabelCollisions(t *testing.T) {
+	// Five thin wedges around 3 o'clock and two around 9 o'clock: Labels on
+	// each side are pushed apart by 12 (font height plus a quarter) and
+	// aligned at the same x.
+	var wedges []Wedgeinfo
+	for i, a := range []float64{-0.1, -0.05, 0, 0.05, 0.1, math.Pi - 0.05, math.Pi + 0.05} {
+		wedges = append(wedges, Wedgeinfo{Phi: a - 0.02, Psi: a + 0.02, Text: string(rune('A' + i)), Tp: "o"})
+	}
+	wedges = append(wedges, Wedgeinfo{Phi: 1, Psi: 2, Text: "in", Tp: "i"})
+	r := &textRecorder{}
+	GenericPieLabels(r, wedges, 200, 200, 100, 1)
+	got := strings.Join(r.texts, " ")
+	expected := "G@87,196cr F@87,208cr A@313,190cl B@313,202cl C@313,214cl D@313,226cl E@313,238cl"
+	if got != expected {
+		t.Errorf("got %q, expected %q", got, expected)
+	}
+
+	// Labels pushed below the pie are moved up again.
+	wedges = wedges[:0]
+	for i := 0; i < 8; i++ {
+		a := math.Pi/2 - 0.3 + 0.02*float64(i)
+		wedges = append(wedges, Wedgeinfo{Phi: a - 0.01, Psi: a + 0.01, Text: "x", Tp: "o"})
+	}
+	r.texts = nil
+	GenericPieLabels(r, wedges, 200, 200, 100, 1)
+	if last := r.texts[len(r.texts)-1]; last != "x@313,320cl" {
+		t.Errorf("lowest label %q, expected below pie at y=320", last)
+	}
+}
+
+func TestPieCatLabels(t *testing.T) {
+	pct := func(v, sum float64) string { return fmt.Sprintf("%.0f%%", 100*v/sum) }
+	for i, tc := range []struct {
+		cat      bool
+		fmtVal   func(v, sum float64) string
+		expected string
+	}{
+		{false, nil, `["" ""] ["" ""]`},
+		{true, nil, `["A" "B"] ["X" "Y"]`},
+		{false, pct, `["25%" "75%"] ["50%" "50%"]`},
+		{true, pct, `["A 25%" "B 75%"] ["X 50%" "Y 50%"]`},
+	} {
+		c := PieChart{CatLabels: tc.cat, FmtVal: tc.fmtVal, LabelPos: OutsideLabels}
+		c.AddDataPair("outer", []string{"A", "B"}, []float64{1, 3})
+		c.AddDataPair("inner", []string{"X", "Y"}, []float64{2, 2})
+		labels, out := c.labels(nil, 100)
+		got := fmt.Sprintf("%q %q", labels[0], labels[1])
+		if got != tc.expected {
+			t.Errorf("%d: got %s, expected %s", i, got, tc.expected)
+		}
+		if want := tc.cat || tc.fmtVal != nil; out[0] != want || out[1] != want {
+			t.Errorf("%d: outside %v, expected %t", i, out, want)
+		}
+	}
+}
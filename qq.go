@@ -0,0 +1,85 @@
+package chart
+
+import (
+	"image/color"
+	"math"
+)
+
+// NormalQuantile returns the quantile function of the normal distribution
+// with the given mean and standard deviation, e.g. for AddQQ.
+func NormalQuantile(mean, sd float64) func(p float64) float64 {
+	return func(p float64) float64 {
+		return mean + sd*math.Sqrt2*math.Erfinv(2*p-1)
+	}
+}
+
+// ExponentialQuantile returns the quantile function of the exponential
+// distribution with the given mean.
+func ExponentialQuantile(mean float64) func(p float64) float64 {
+	return func(p float64) float64 {
+		return -mean * math.Log(1-p)
+	}
+}
+
+// UniformQuantile returns the quantile function of the uniform distribution
+// on [a,b].
+func UniformQuantile(a, b float64) func(p float64) float64 {
+	return func(p float64) float64 {
+		return a + p*(b-a)
+	}
+}
+
+// qqPositions are the plotting positions (i-0.5)/n of n quantiles.
+func qqPositions(n int) []float64 {
+	ps := make([]float64, n)
+	for i := range ps {
+		ps[i] = (float64(i) + 0.5) / float64(n)
+	}
+	return ps
+}
+
+// AddQQ adds a quantile-quantile plot of data against the theoretical
+// distribution with quantile function quantile (e.g. NormalQuantile(
+// Mean(data), StdDev(data))): The i'th smallest of n samples is plotted
+// (vertically) against the theoretical (i-0.5)/n quantile. The identity line
+// is added with the first Q-Q plot. A key entry is produced if name is not
+// empty; an empty style is generated by AutoStyle.
+func (c *ScatterChart) AddQQ(name string, data []float64, quantile func(p float64) float64, style Style) {
+	sorted := sortedSamples(data)
+	points := make([]EPoint, len(sorted))
+	nan := math.NaN()
+	for i, p := range qqPositions(len(sorted)) {
+		points[i] = EPoint{X: quantile(p), Y: QuantileSorted(sorted, p, QuantileType5), DeltaX: nan, DeltaY: nan}
+	}
+	c.addQQ(name, points, style)
+}
+
+// AddQQSamples adds a two-sample quantile-quantile plot: The quantiles of y
+// (vertically) against those of x at the plotting positions (i-0.5)/m where
+// m is the size of the smaller sample. Apart from that it works like AddQQ.
+func (c *ScatterChart) AddQQSamples(name string, x, y []float64, style Style) {
+	sx, sy := sortedSamples(x), sortedSamples(y)
+	points := make([]EPoint, imin(len(sx), len(sy)))
+	nan := math.NaN()
+	for i, p := range qqPositions(len(points)) {
+		points[i] = EPoint{X: QuantileSorted(sx, p, QuantileType5), Y: QuantileSorted(sy, p, QuantileType5),
+			DeltaX: nan, DeltaY: nan}
+	}
+	c.addQQ(name, points, style)
+}
+
+func (c *ScatterChart) addQQ(name string, points []EPoint, style Style) {
+	if style.empty() {
+		n := len(c.Data)
+		if c.identity {
+			n-- // the identity line does not use up a style
+		}
+		style = AutoStyle(n, false)
+	}
+	c.AddData(name, points, PlotStylePoints, style)
+	if !c.identity {
+		c.identity = true
+		c.AddFunc("", func(x float64) float64 { return x }, PlotStyleLines,
+			Style{Symbol: '.', LineColor: color.NRGBA{0x80, 0x80, 0x80, 0xff}, LineWidth: 1, LineStyle: DashedLine})
+	}
+}
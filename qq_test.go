@@ -0,0 +1,36 @@
+package chart
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestQQ(t *testing.T) {
+	q := NormalQuantile(10, 2)
+	if math.Abs(q(0.975)-(10+2*1.959964)) > 1e-5 || q(0.5) != 10 {
+		t.Errorf("Normal quantile: Got %g %g", q(0.975), q(0.5))
+	}
+
+	c := ScatterChart{}
+	c.AddQQ("", []float64{4, 1, 3, 2}, UniformQuantile(0, 4), Style{})
+	c.AddQQSamples("", []float64{1, 2, 3, 4}, []float64{10, 20, 30, 40, 50, 60, 70, 80}, Style{})
+	if len(c.Data) != 3 || c.Data[1].Func == nil {
+		t.Fatalf("Expected two data sets and identity line, got %d", len(c.Data))
+	}
+	got := ""
+	for _, d := range c.Data {
+		for _, p := range d.Samples {
+			got += fmt.Sprintf(" %g,%g", p.X, p.Y)
+		}
+	}
+	if want := " 0.5,1 1.5,2 2.5,3 3.5,4 1,15 2,35 3,55 4,75"; got != want {
+		t.Errorf("Got %s, expected %s", got, want)
+	}
+	if c.Data[2].Style != AutoStyle(1, false) {
+		t.Errorf("Second data set got style %v", c.Data[2].Style)
+	}
+	if c.XRange.DataMin != 0.5 || c.YRange.DataMax != 75 {
+		t.Errorf("Bad autoscaling %g %g", c.XRange.DataMin, c.YRange.DataMax)
+	}
+}
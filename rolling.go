@@ -0,0 +1,127 @@
+package chart
+
+import (
+	"math"
+	"sort"
+)
+
+// RollingStat selects the statistic calculated by a Rolling window.
+type RollingStat int
+
+const (
+	RollingMean          RollingStat = iota // arithmetic mean of the window
+	RollingMedian                           // median of the window
+	RollingPercentile                       // percentile P of the window
+	ExponentialSmoothing                    // s[i] = alpha*y[i] + (1-alpha)*s[i-1], no window
+)
+
+// Rolling describes a series derived from a data set by a statistic of a
+// sliding window of samples: The window contains either the last Count
+// samples or (if Duration is set) all samples with x in (x[i]-Duration,
+// x[i]]. X values are seconds on time axes. The first windows contain
+// fewer samples. Samples with NaN values are ignored.
+type Rolling struct {
+	Stat     RollingStat
+	Count    int       // number of samples in the window, 0 is 10
+	Duration TimeDelta // window of this duration instead of Count samples
+	Centered bool      // window centered on x[i] instead of ending at x[i]
+	P        float64   // percentile (0...100) of RollingPercentile
+
+	// Alpha is the smoothing factor of ExponentialSmoothing. If 0 it is
+	// 2/(Count+1) or, with Duration, depends on the distance of the
+	// samples: 1-exp(-(x[i]-x[i-1])/Duration), suitable for irregular
+	// time series.
+	Alpha float64
+}
+
+// Apply returns the derived series of data, sorted by x.
+func (r Rolling) Apply(data []EPoint) []EPoint {
+	x, y := make([]float64, 0, len(data)), make([]float64, 0, len(data))
+	for _, p := range data {
+		if !math.IsNaN(p.X) && !math.IsNaN(p.Y) {
+			x, y = append(x, p.X), append(y, p.Y)
+		}
+	}
+	sort.Stable(weightedSamples{x, y})
+
+	n := len(x)
+	count := r.Count
+	if count <= 0 {
+		count = 10
+	}
+	var d float64
+	if r.Duration != nil {
+		d = float64(r.Duration.Seconds())
+	}
+
+	nan := math.NaN()
+	out := make([]EPoint, n)
+	if r.Stat == ExponentialSmoothing {
+		alpha := r.Alpha
+		if alpha <= 0 && d == 0 {
+			alpha = 2 / float64(count+1)
+		}
+		s := 0.0
+		for i := range x {
+			a := alpha
+			if a <= 0 && i > 0 {
+				a = 1 - math.Exp(-(x[i]-x[i-1])/d)
+			}
+			if i == 0 {
+				a = 1
+			}
+			s = a*y[i] + (1-a)*s
+			out[i] = EPoint{X: x[i], Y: s, DeltaX: nan, DeltaY: nan}
+		}
+		return out
+	}
+
+	lo, hi := 0, 0 // window is [lo,hi)
+	win := make([]float64, 0, count)
+	for i := range x {
+		if d > 0 {
+			from, to := x[i]-d, x[i]
+			if r.Centered {
+				from, to = x[i]-d/2, x[i]+d/2
+			}
+			for lo < n && (x[lo] < from || x[lo] == from && !r.Centered) {
+				lo++
+			}
+			for hi < n && x[hi] <= to {
+				hi++
+			}
+		} else {
+			lo, hi = i-count+1, i+1
+			if r.Centered {
+				lo, hi = i-count/2, i-count/2+count
+			}
+			lo, hi = imax(lo, 0), imin(hi, n)
+		}
+
+		win = append(win[:0], y[lo:hi]...)
+		var v float64
+		switch r.Stat {
+		case RollingMedian:
+			sort.Float64s(win)
+			v = QuantileSorted(win, 0.5, DefaultQuantile)
+		case RollingPercentile:
+			sort.Float64s(win)
+			v = QuantileSorted(win, r.P/100, DefaultQuantile)
+		default:
+			v = sum(win) / float64(len(win))
+		}
+		out[i] = EPoint{X: x[i], Y: v, DeltaX: nan, DeltaY: nan}
+	}
+	return out
+}
+
+// AddRolling adds the series derived from data set i by r as a line. A
+// key entry is produced if name is not empty. An empty style draws a line
+// of width 2 in the color of data set i.
+func (c *ScatterChart) AddRolling(name string, i int, r Rolling, style Style) {
+	if style.empty() {
+		style = c.derivedStyle(i)
+		style.LineWidth = 2
+	}
+	c.AddData(name, r.Apply(c.Data[i].Samples), PlotStyleLines, style)
+}
@@ -0,0 +1,44 @@
+package chart
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestRolling(t *testing.T) {
+	var data []EPoint
+	for i, y := range []float64{1, 2, 3, 10, 5, 6} {
+		data = append(data, EPoint{X: float64(60 * i), Y: y})
+	}
+	data = append(data, EPoint{X: 30, Y: math.NaN()})
+	for _, tc := range []struct {
+		r        Rolling
+		expected string
+	}{
+		{Rolling{Count: 3}, "[1 1.5 2 5 6 7]"},
+		{Rolling{Count: 3, Centered: true}, "[1.5 2 5 6 7 5.5]"},
+		{Rolling{Stat: RollingMedian, Count: 3}, "[1 1.5 2 3 5 6]"},
+		{Rolling{Stat: RollingPercentile, P: 100, Count: 2}, "[1 2 3 10 10 6]"},
+		{Rolling{Duration: Minute{2}}, "[1 1.5 2.5 6.5 7.5 5.5]"},
+		{Rolling{Duration: Minute{2}, Centered: true}, "[1.5 2 5 6 7 5.5]"},
+		{Rolling{Stat: ExponentialSmoothing, Alpha: 0.5}, "[1 1.5 2.25 6.125 5.5625 5.78125]"},
+		{Rolling{Stat: ExponentialSmoothing, Count: 3}, "[1 1.5 2.25 6.125 5.5625 5.78125]"},
+	} {
+		var got []float64
+		for _, p := range tc.r.Apply(data) {
+			got = append(got, p.Y)
+		}
+		if s := fmt.Sprintf("%v", got); s != tc.expected {
+			t.Errorf("%+v: Got %s, expected %s", tc.r, s, tc.expected)
+		}
+	}
+
+	// Duration based smoothing: Equidistant samples are smoothed like a
+	// constant alpha of 1-exp(-1).
+	alpha := 1 - math.Exp(-1)
+	got := Rolling{Stat: ExponentialSmoothing, Duration: Minute{1}}.Apply(data)
+	if want := alpha*2 + (1-alpha)*1; math.Abs(got[1].Y-want) > 1e-12 {
+		t.Errorf("Got %g, expected %g", got[1].Y, want)
+	}
+}
@@ -12,6 +12,18 @@ type ScatterChart struct {
 	Options        PlotOptions
 	Data           []ScatterChartData // The actual data (filled with Add...-methods)
 	NSamples       int                // number of samples for function plots
+	Annotations    Annotations        // text, arrows, reference lines and spans
+
+	Decimation      Decimation // reduction of long series, see Decimation
+	DecimationLimit int        // series with more points are decimated (LTTB: to this many points), 0 is twice the plot width
+
+	// Lines are broken at samples with NaN values and between consecutive
+	// samples whose x values differ by more than Gap (or by more than
+	// GapDelta on time axes). Zero values do not break lines.
+	Gap      float64
+	GapDelta TimeDelta
+
+	identity bool // the identity line of Q-Q plots has been added
 }
 
 // ScatterChartData encapsulates a data set or function in a scatter chart.
@@ -119,6 +131,20 @@ func (c *ScatterChart) AddDataPair(name string, x, y []float64, plotstyle PlotSt
 	c.AddData(name, data, plotstyle, style)
 }
 
+// derivedStyle returns the style of lines derived from data set i (fits,
+// rolling statistics): A solid line in the color of data set i.
+func (c *ScatterChart) derivedStyle(i int) Style {
+	data := c.Data[i].Style
+	style := Style{Symbol: '-', LineColor: data.LineColor, LineWidth: 1, LineStyle: SolidLine}
+	if style.LineColor == nil {
+		style.LineColor = data.SymbolColor
+	}
+	if style.LineColor == nil {
+		style.LineColor = AutoStyle(i, false).SymbolColor
+	}
+	return style
+}
+
 // Reset chart to state before plotting.
 func (c *ScatterChart) Reset() {
 	c.XRange.Reset()
@@ -127,10 +153,7 @@ func (c *ScatterChart) Reset() {
 
 // Plot outputs the scatter chart to the graphic output g.
 func (c *ScatterChart) Plot(g Graphics) {
-	layout := layout(g, c.Title, c.XRange.Label, c.YRange.Label,
-		c.XRange.TicSetting.Hide || c.XRange.TicSetting.HideLabels,
-		c.YRange.TicSetting.Hide || c.YRange.TicSetting.HideLabels,
-		&c.Key)
+	layout := layout(g, c.Title, &c.XRange, &c.YRange, &c.Key, c.Options)
 
 	width, height := layout.Width, layout.Height
 	topm, leftm := layout.Top, layout.Left
@@ -147,33 +170,55 @@ func (c *ScatterChart) Plot(g Graphics) {
 		drawTitle(g, c.Title, elementStyle(c.Options, TitleElement))
 	}
 
+	drawAnnotations(g, c.Annotations, BackgroundLayer, c.XRange, c.YRange, c.Options)
 	g.XAxis(c.XRange, topm+height, topm, c.Options)
 	g.YAxis(c.YRange, leftm, leftm+width, c.Options)
+	drawAnnotations(g, c.Annotations, BelowDataLayer, c.XRange, c.YRange, c.Options)
 
 	// Plot Data
 	xf, yf := c.XRange.Data2Screen, c.YRange.Data2Screen
 	xmin, xmax := c.XRange.Min, c.XRange.Max
 	ymin, ymax := c.YRange.Min, c.YRange.Max
 	spf := screenPointFunc(xf, yf, xmin, xmax, ymin, ymax)
+	limit := c.DecimationLimit
+	if limit <= 0 {
+		limit = 2 * width
+	}
+	gap := c.Gap
+	if c.GapDelta != nil {
+		gap = float64(c.GapDelta.Seconds())
+	}
 
 	for i, data := range c.Data {
 		style := data.Style
 		if data.Samples != nil {
 			// Samples
 			points := make([]EPoint, 0, len(data.Samples))
+			lastX := math.NaN()
 			for _, d := range data.Samples {
+				if math.IsNaN(d.X) || math.IsNaN(d.Y) {
+					points, lastX = lineBreak(points), math.NaN()
+					continue
+				}
+				if gap > 0 && math.Abs(d.X-lastX) > gap {
+					points = lineBreak(points)
+				}
+				lastX = d.X
 				if d.X < xmin || d.X > xmax || d.Y < ymin || d.Y > ymax {
 					continue
 				}
 				p := spf(d)
 				points = append(points, p)
 			}
-			g.Scatter(points, data.PlotStyle, style)
+			points = decimate(points, data.PlotStyle, c.Decimation, limit)
+			scatterSegments(g, points, data.PlotStyle, style)
 		} else if data.Func != nil {
 			c.drawFunction(g, i)
 		}
 	}
 
+	drawAnnotations(g, c.Annotations, AboveDataLayer, c.XRange, c.YRange, c.Options)
+
 	if !c.Key.Hide {
 		g.Key(layout.KeyX, layout.KeyY, c.Key, c.Options)
 	}
@@ -220,8 +265,7 @@ func (c *ScatterChart) drawFunction(g Graphics, i int) {
 
 		// Handle NaN and +/- Inf
 		if math.IsNaN(y) {
-			g.Scatter(points, plotstyle, style)
-			points = points[0:0]
+			points = lineBreak(points)
 			lastP = nil
 			continue
 		}
@@ -246,21 +290,46 @@ func (c *ScatterChart) drawFunction(g Graphics, i int) {
 			if lastIn {
 				pc := c.clipPoint(*lastP, EPoint{X: sx, Y: sy}, symin, symax)
 				points = append(points, pc)
-				g.Scatter(points, plotstyle, style)
 				// fmt.Printf("Added clip point %v and drawing\n", pc)
-				points = points[0:0]
+				points = lineBreak(points)
 				lastIn = false
 			} else if (lastP.Y < symin && sy > symax) || (lastP.Y > symax && sy < symin) {
 				p2 := c.clip2Point(*lastP, EPoint{X: sx, Y: sy}, symin, symax)
 				// fmt.Printf("Added 2clip points %v / %v and drawing\n", p2[0], p2[1])
-				g.Scatter(p2, plotstyle, style)
+				points = lineBreak(append(points, p2...))
 			}
 
 		}
 
 		lastP = &EPoint{X: sx, Y: sy}
 	}
-	g.Scatter(points, plotstyle, style)
+	scatterSegments(g, points, plotstyle, style)
+}
+
+// lineBreak appends a point with NaN coordinates to points which breaks
+// the line drawn through points (see scatterSegments). Leading and repeated
+// breaks are omitted.
+func lineBreak(points []EPoint) []EPoint {
+	if n := len(points); n == 0 || math.IsNaN(points[n-1].X) {
+		return points
+	}
+	nan := math.NaN()
+	return append(points, EPoint{X: nan, Y: nan, DeltaX: nan, DeltaY: nan})
+}
+
+// scatterSegments draws the segments of points separated by line breaks
+// with one call to g.Scatter each.
+func scatterSegments(g Graphics, points []EPoint, plotstyle PlotStyle, style Style) {
+	start := 0
+	for i, p := range points {
+		if math.IsNaN(p.X) {
+			g.Scatter(points[start:i], plotstyle, style)
+			start = i + 1
+		}
+	}
+	if start < len(points) || start == 0 {
+		g.Scatter(points[start:], plotstyle, style)
+	}
 }
 
 // Point in is in valid y range, out is out. Return p which clips the line from in to out to valid y range
@@ -2,6 +2,7 @@ package chart
 
 import (
 	"math"
+	"math/rand"
 	"sort"
 )
 
@@ -32,29 +33,251 @@ func PercentilInt(data []int, p int) int {
 }
 
 // Return p percentil of pre-sorted float64 data. 0 <= p <= 100.
-func percentilFloat64(data []float64, p int) float64 {
+func percentilFloat64(data []float64, p float64) float64 {
+	return QuantileSorted(data, p/100, QuantileType6)
+}
+
+// QuantileMethod selects one of the nine definitions of sample quantiles
+// of Hyndman and Fan (1996). Types 1 to 3 are discontinuous, types 4 to 9
+// interpolate linearly between order statistics.
+type QuantileMethod int
+
+const (
+	DefaultQuantile QuantileMethod = iota // same as QuantileType7
+	QuantileType1                         // inverse of empirical distribution function
+	QuantileType2                         // like type 1 with averaging at discontinuities
+	QuantileType3                         // nearest even order statistic (SAS)
+	QuantileType4                         // linear interpolation of the empirical distribution function
+	QuantileType5                         // piecewise linear with knots at midpoints (Hazen)
+	QuantileType6                         // p(n+1) (Weibull, Minitab, SPSS), used by SixvalFloat64
+	QuantileType7                         // 1+p(n-1) (Gumbel, R and Excel default)
+	QuantileType8                         // approximately median-unbiased (recommended by Hyndman and Fan)
+	QuantileType9                         // approximately unbiased for normal data (Blom)
+)
+
+// QuantileSorted returns the p quantile (0 <= p <= 1) of the pre-sorted
+// data calculated by method.
+func QuantileSorted(data []float64, p float64, method QuantileMethod) float64 {
 	n := len(data)
 	if n == 0 {
-		return 0
+		return math.NaN()
 	}
-	if n == 1 {
-		return data[0]
+	fn := float64(n)
+
+	// Position np+m of the quantile as 1-based index j plus fraction g.
+	var m float64
+	switch method {
+	case QuantileType3:
+		m = -0.5
+	case QuantileType5:
+		m = 0.5
+	case QuantileType6:
+		m = p
+	case QuantileType1, QuantileType2, QuantileType4:
+		m = 0
+	case QuantileType8:
+		m = (p + 1) / 3
+	case QuantileType9:
+		m = p/4 + 3.0/8
+	default:
+		m = 1 - p
 	}
+	pos := fn*p + m
+	j := math.Floor(pos)
+	g := pos - j
 
-	pos := float64(p) * float64(n+1) / 100
-	fpos := math.Floor(pos)
-	intPos := int(fpos)
-	dif := pos - fpos
-	if intPos < 1 {
+	// at returns the j'th order statistic (1-based) clamped to the data.
+	at := func(j float64) float64 {
+		return data[clip(int(j)-1, 0, n-1)]
+	}
+
+	switch method {
+	case QuantileType1:
+		if g == 0 {
+			return at(j)
+		}
+		return at(j + 1)
+	case QuantileType2:
+		if g == 0 {
+			return (at(j) + at(j+1)) / 2
+		}
+		return at(j + 1)
+	case QuantileType3:
+		if g == 0 && int(j)%2 == 0 {
+			return at(j)
+		}
+		return at(j + 1)
+	}
+	if j < 1 {
 		return data[0]
 	}
-	if intPos >= n {
+	if j >= fn {
 		return data[n-1]
 	}
-	lower := data[intPos-1]
-	upper := data[intPos]
-	val := lower + dif*(upper-lower)
-	return val
+	return at(j) + g*(at(j+1)-at(j))
+}
+
+// Quantile returns the p quantile (0 <= p <= 1) of data calculated by
+// method. Data need not be sorted and is not modified.
+func Quantile(data []float64, p float64, method QuantileMethod) float64 {
+	return Quantiles(data, []float64{p}, method)[0]
+}
+
+// Quantiles returns the quantiles of data for all p in ps. Data need not be
+// sorted and is not modified.
+func Quantiles(data []float64, ps []float64, method QuantileMethod) []float64 {
+	sorted := make([]float64, len(data))
+	copy(sorted, data)
+	sort.Float64s(sorted)
+	q := make([]float64, len(ps))
+	for i, p := range ps {
+		q[i] = QuantileSorted(sorted, p, method)
+	}
+	return q
+}
+
+// Mean returns the arithmetic mean of data.
+func Mean(data []float64) float64 {
+	if len(data) == 0 {
+		return math.NaN()
+	}
+	mean, _, _ := weightedMoments(data, nil)
+	return mean
+}
+
+// Variance returns the (unbiased) sample variance of data.
+func Variance(data []float64) float64 {
+	sd := StdDev(data)
+	return sd * sd
+}
+
+// StdDev returns the sample standard deviation of data, i.e. the square
+// root of Variance.
+func StdDev(data []float64) float64 {
+	if len(data) < 2 {
+		return math.NaN()
+	}
+	_, sd, _ := weightedMoments(data, nil)
+	return sd
+}
+
+// Skewness returns the (moment coefficient of) skewness of data.
+func Skewness(data []float64) float64 {
+	if len(data) < 2 {
+		return math.NaN()
+	}
+	_, _, skew := weightedMoments(data, nil)
+	return skew
+}
+
+// MAD returns the median absolute deviation of data from its median. For
+// normally distributed data 1.4826*MAD estimates the standard deviation.
+func MAD(data []float64) float64 {
+	if len(data) == 0 {
+		return math.NaN()
+	}
+	med := Quantile(data, 0.5, DefaultQuantile)
+	dev := make([]float64, len(data))
+	for i, v := range data {
+		dev[i] = math.Abs(v - med)
+	}
+	return Quantile(dev, 0.5, DefaultQuantile)
+}
+
+// BootstrapCI returns the percentile bootstrap confidence interval of
+// the statistic stat (e.g. Mean or a median) of data at the given level
+// (e.g. 0.95) from resamples (0: 1000) resamples drawn with rng. A nil rng
+// uses a fixed seed so that charts are reproducible.
+func BootstrapCI(data []float64, stat func([]float64) float64, level float64, resamples int, rng *rand.Rand) (lo, hi float64) {
+	n := len(data)
+	if n == 0 {
+		return math.NaN(), math.NaN()
+	}
+	if resamples <= 0 {
+		resamples = 1000
+	}
+	if rng == nil {
+		rng = rand.New(rand.NewSource(1))
+	}
+	stats := make([]float64, resamples)
+	sample := make([]float64, n)
+	for r := range stats {
+		for i := range sample {
+			sample[i] = data[rng.Intn(n)]
+		}
+		stats[r] = stat(sample)
+	}
+	sort.Float64s(stats)
+	alpha := (1 - level) / 2
+	return QuantileSorted(stats, alpha, DefaultQuantile), QuantileSorted(stats, 1-alpha, DefaultQuantile)
+}
+
+// weightedQuantile returns the q quantile (0 <= q <= 1) of the pre-sorted
+// data where data[i] has weight weights[i] (nil weights are all 1). It
+// interpolates linearly between closest ranks as if each sample was
+// repeated weight times.
+func weightedQuantile(data, weights []float64, q float64) float64 {
+	n := len(data)
+	if n == 0 {
+		return 0
+	}
+	cum := make([]float64, n)
+	total := 0.0
+	for i := range data {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		total += w
+		cum[i] = total
+	}
+	pos := q * (total - 1)
+	// at returns the value at position p in the expanded data.
+	at := func(p float64) float64 {
+		i := sort.Search(n, func(i int) bool { return cum[i] > p })
+		if i >= n {
+			i = n - 1
+		}
+		return data[i]
+	}
+	if pos <= 0 {
+		return data[0]
+	}
+	lo := math.Floor(pos)
+	return at(lo) + (pos-lo)*(at(lo+1)-at(lo))
+}
+
+// weightedMoments returns the mean, the (sample) standard deviation and
+// the skewness of data weighted by weights (nil weights are all 1).
+func weightedMoments(data, weights []float64) (mean, sd, skew float64) {
+	w := func(i int) float64 {
+		if weights == nil {
+			return 1
+		}
+		return weights[i]
+	}
+	n := 0.0
+	for i, v := range data {
+		mean += w(i) * v
+		n += w(i)
+	}
+	if n == 0 {
+		return
+	}
+	mean /= n
+	var m2, m3 float64
+	for i, v := range data {
+		d := v - mean
+		m2 += w(i) * d * d
+		m3 += w(i) * d * d * d
+	}
+	if n > 1 {
+		sd = math.Sqrt(m2 / (n - 1))
+	}
+	if m2 > 0 {
+		skew = (m3 / n) / math.Pow(m2/n, 1.5)
+	}
+	return
 }
 
 // Compute minimum, p percentil, median, average, 100-p percentil and maximum of values in data.
@@ -153,7 +376,80 @@ func SixvalFloat64(data []float64, p int) (min, lq, med, avg, uq, max float64) {
 	if p > 100 {
 		p = 100
 	}
-	lq = percentilFloat64(data, p)
-	uq = percentilFloat64(data, 100-p)
+	lq = percentilFloat64(data, float64(p))
+	uq = percentilFloat64(data, float64(100-p))
 	return
 }
+
+// studentTQuantile returns the p quantile (0 < p < 1) of Student's t
+// distribution with df degrees of freedom.
+func studentTQuantile(p, df float64) float64 {
+	if df <= 0 || p <= 0 || p >= 1 {
+		return math.NaN()
+	}
+	cdf := func(t float64) float64 {
+		tail := betaInc(df/2, 0.5, df/(df+t*t)) / 2
+		if t > 0 {
+			return 1 - tail
+		}
+		return tail
+	}
+	// Bisection: The cdf is monotonic.
+	lo, hi := -1e4, 1e4
+	for i := 0; i < 100 && hi-lo > 1e-10; i++ {
+		mid := (lo + hi) / 2
+		if cdf(mid) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// betaInc returns the regularized incomplete beta function I_x(a,b)
+// evaluated by its continued fraction (modified Lentz's method).
+func betaInc(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	if x > (a+1)/(a+b+2) {
+		return 1 - betaInc(b, a, 1-x) // continued fraction converges faster
+	}
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	lgab, _ := math.Lgamma(a + b)
+	front := math.Exp(lgab-lga-lgb+a*math.Log(x)+b*math.Log(1-x)) / a
+
+	const tiny = 1e-300
+	f, c, d := 1.0, 1.0, 0.0
+	for i := 0; i <= 300; i++ {
+		m := float64(i / 2)
+		var num float64
+		switch {
+		case i == 0:
+			num = 1
+		case i%2 == 0:
+			num = m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m))
+		default:
+			num = -(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1))
+		}
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		d = 1 / d
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		f *= c * d
+		if math.Abs(1-c*d) < 1e-14 {
+			return front * (f - 1)
+		}
+	}
+	return front * (f - 1)
+}
@@ -0,0 +1,71 @@
+package chart
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQuantileMethods(t *testing.T) {
+	data := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	// Reference values from R's quantile(1:10, c(0.1, 0.5), type=...).
+	for _, tc := range []struct {
+		method   QuantileMethod
+		p10, p50 float64
+	}{
+		{QuantileType1, 1, 5},
+		{QuantileType2, 1.5, 5.5},
+		{QuantileType3, 1, 5},
+		{QuantileType4, 1, 5},
+		{QuantileType5, 1.5, 5.5},
+		{QuantileType6, 1.1, 5.5},
+		{QuantileType7, 1.9, 5.5},
+		{QuantileType8, 1.366667, 5.5},
+		{QuantileType9, 1.4, 5.5},
+		{DefaultQuantile, 1.9, 5.5},
+	} {
+		q := Quantiles(data, []float64{0.1, 0.5}, tc.method)
+		if math.Abs(q[0]-tc.p10) > 1e-6 || math.Abs(q[1]-tc.p50) > 1e-6 {
+			t.Errorf("Type %d: Got %g %g, expected %g %g", tc.method, q[0], q[1], tc.p10, tc.p50)
+		}
+	}
+	if q := Quantile([]float64{3, 1, 2}, 1, QuantileType7); q != 3 {
+		t.Errorf("Maximum: Got %g", q)
+	}
+}
+
+func TestSummaryStats(t *testing.T) {
+	data := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	for _, tc := range []struct {
+		name          string
+		got, expected float64
+	}{
+		{"Mean", Mean(data), 5},
+		{"Variance", Variance(data), 32.0 / 7},
+		{"StdDev", StdDev(data), math.Sqrt(32.0 / 7)},
+		{"MAD", MAD(data), 0.5},
+	} {
+		if math.Abs(tc.got-tc.expected) > 1e-9 {
+			t.Errorf("%s: Got %g, expected %g", tc.name, tc.got, tc.expected)
+		}
+	}
+	if s := Skewness(data); s <= 0 {
+		t.Errorf("Skewness of right skewed data: Got %g", s)
+	}
+	if s := Skewness([]float64{1, 2, 3}); math.Abs(s) > 1e-12 {
+		t.Errorf("Skewness of symmetric data: Got %g", s)
+	}
+}
+
+func TestBootstrapCI(t *testing.T) {
+	data := make([]float64, 200)
+	for i := range data {
+		data[i] = float64(i % 20)
+	}
+	lo, hi := BootstrapCI(data, Mean, 0.95, 500, nil)
+	if !(lo < 9.5 && 9.5 < hi) || hi-lo > 2 {
+		t.Errorf("Got %g .. %g", lo, hi)
+	}
+	if lo2, hi2 := BootstrapCI(data, Mean, 0.95, 500, nil); lo2 != lo || hi2 != hi {
+		t.Errorf("Not reproducible")
+	}
+}
@@ -60,10 +60,7 @@ func (sc *StripChart) Plot(g Graphics) {
 
 	if sc.Jitter {
 		// Set up ranging
-		layout := layout(g, sc.Title, sc.XRange.Label, sc.YRange.Label,
-			sc.XRange.TicSetting.Hide || sc.XRange.TicSetting.HideLabels,
-			sc.YRange.TicSetting.Hide || sc.YRange.TicSetting.HideLabels,
-			&sc.Key)
+		layout := layout(g, sc.Title, &sc.XRange, &sc.YRange, &sc.Key, sc.Options)
 
 		_, height := layout.Width, layout.Height
 		topm, _ := layout.Top, layout.Left
@@ -198,6 +198,9 @@ const (
 	KeyElement
 	TitleElement
 	RangeLimitElement
+	AnnotationElement
+	ReferenceLineElement
+	SpanElement
 )
 
 // PlotOptions contains a Style for each PlotElement. If a PlotOption does not
@@ -231,6 +234,12 @@ var DefaultOptions = map[PlotElement]Style{
 	TitleElement: Style{LineColor: color.NRGBA{0, 0, 0, 0xff}, LineWidth: 1, LineStyle: SolidLine,
 		FillColor: color.NRGBA{0xec, 0xc7, 0x50, 0xff}, Font: Font{Size: LargeFontSize}},
 	RangeLimitElement: Style{Font: Font{Size: SmallFontSize}},
+	AnnotationElement: Style{LineColor: color.NRGBA{0x20, 0x20, 0x20, 0xff}, LineWidth: 1, LineStyle: SolidLine,
+		Font: Font{Size: SmallFontSize}},
+	ReferenceLineElement: Style{LineColor: color.NRGBA{0x60, 0x60, 0x60, 0xff}, LineWidth: 1, LineStyle: DashedLine,
+		Font: Font{Size: SmallFontSize}},
+	SpanElement: Style{LineColor: color.NRGBA{0xa0, 0xa0, 0xa0, 0x40}, LineWidth: 0, FillColor: color.NRGBA{0xa0, 0xa0, 0xa0, 0x40},
+		Font: Font{Size: SmallFontSize}},
 }
 
 func hsv2rgb(h, s, v int) (r, g, b int) {
@@ -0,0 +1,229 @@
+package chart
+
+import (
+	"image/color"
+	"math"
+)
+
+// SunburstNode is a category in the tree of a SunburstChart. The value of
+// a node with children is the sum of the values of its children, Val is
+// used for leaf nodes only.
+type SunburstNode struct {
+	Cat      string
+	Val      float64
+	Style    Style // empty style: AutoStyle for top level nodes, lighter parent style else
+	Children []SunburstNode
+}
+
+// Value returns the value of n which is the sum of its children's values
+// for non-leaf nodes.
+func (n SunburstNode) Value() float64 {
+	if len(n.Children) == 0 {
+		return n.Val
+	}
+	var sum float64
+	for _, c := range n.Children {
+		sum += c.Value()
+	}
+	return sum
+}
+
+// depth returns the number of levels of the tree rooted at n.
+func (n SunburstNode) depth() int {
+	d := 0
+	for _, c := range n.Children {
+		d = imax(d, c.depth())
+	}
+	return d + 1
+}
+
+// SunburstChart draws a hierarchy of categories as concentric rings: The
+// top level categories form the innermost ring and each child spans its
+// share of the angle of its parent in the next ring. Nodes without style
+// inherit the style of their parent with the fill color lightened by
+// Lighten.
+// The FmtVal function is used to format optional labels (see CatLabels) on
+// the segments; the sum passed to FmtVal is the value of the parent node.
+// Labels which do not fit into their segment are omitted.
+type SunburstChart struct {
+	Title   string  // The title
+	Key     Key     // The Key/Legend, lists the top level categories
+	Inner   float64 // relative radius of inner white area
+	Lighten float64 // fraction by which fill colors of children are moved towards white, 0 is 0.25
+	Options PlotOptions
+	Data    []SunburstNode // The top level categories
+
+	FmtVal    func(value, sum float64) string // add value labels to segments
+	CatLabels bool                            // label segments with category name (followed by FmtVal)
+}
+
+// AddNode adds the top level category node (and its children) to the chart.
+// Missing styles of top level nodes are generated by AutoStyle.
+func (c *SunburstChart) AddNode(node SunburstNode) {
+	if node.Style.empty() {
+		node.Style = AutoStyle(len(c.Data), true)
+	}
+	c.Data = append(c.Data, node)
+	c.Key.Entries = append(c.Key.Entries, KeyEntry{PlotStyle: PlotStyleBox, Style: node.Style, Text: node.Cat})
+}
+
+// AddPath adds the value val to the category denoted by path, e.g.
+// []string{"Europe", "France", "Paris"}. Missing nodes along path are created.
+// Values of categories which have (or later get) subcategories are kept in
+// a child category "(other)" as Value sums up the children only.
+func (c *SunburstChart) AddPath(path []string, val float64) {
+	if len(path) == 0 {
+		return
+	}
+	i := 0
+	for i < len(c.Data) && c.Data[i].Cat != path[0] {
+		i++
+	}
+	if i == len(c.Data) {
+		c.AddNode(SunburstNode{Cat: path[0]})
+	}
+	node := &c.Data[i]
+	for _, cat := range path[1:] {
+		node = node.child(cat)
+	}
+	if len(node.Children) > 0 {
+		node = node.child(sunburstOther)
+	}
+	node.Val += val
+}
+
+// sunburstOther is the category which keeps the value of an inner node.
+const sunburstOther = "(other)"
+
+// child returns the child category cat of n, which is created if missing.
+// A value of the leaf n is moved to the new child "(other)".
+func (n *SunburstNode) child(cat string) *SunburstNode {
+	for j := range n.Children {
+		if n.Children[j].Cat == cat {
+			return &n.Children[j]
+		}
+	}
+	if len(n.Children) == 0 && n.Val != 0 && cat != sunburstOther {
+		n.Children = append(n.Children, SunburstNode{Cat: sunburstOther, Val: n.Val})
+		n.Val = 0
+	}
+	n.Children = append(n.Children, SunburstNode{Cat: cat})
+	return &n.Children[len(n.Children)-1]
+}
+
+// Reset chart to state before plotting.
+func (c *SunburstChart) Reset() {}
+
+// Plot outputs the sunburst chart c to g.
+func (c *SunburstChart) Plot(g Graphics) {
+	layout := layout(g, c.Title, nil, nil, &c.Key, c.Options)
+
+	width, height := layout.Width, layout.Height
+	topm, leftm := layout.Top, layout.Left
+
+	r := imin(height, width) / 2
+	x0, y0 := leftm+r, topm+r
+
+	depth := 0
+	for _, n := range c.Data {
+		depth = imax(depth, n.depth())
+	}
+	ri := 0
+	if c.Inner > 0 {
+		ri = int(float64(r) * c.Inner)
+	}
+
+	// Collect the wedges of each ring.
+	rings := make([][]Wedgeinfo, depth)
+	radius := func(level int) int { return ri + (r-ri)*level/imax(1, depth) }
+	var sum float64
+	for _, n := range c.Data {
+		sum += n.Value()
+	}
+	c.wedges(g, rings, c.Data, sum, -math.Pi, 2*math.Pi, 0, radius)
+
+	g.Begin()
+
+	if c.Title != "" {
+		drawTitle(g, c.Title, elementStyle(c.Options, TitleElement))
+	}
+
+	// Outermost ring first as text drivers align the pie with the first ring.
+	for level := depth - 1; level >= 0; level-- {
+		if len(rings[level]) > 0 {
+			g.Rings(rings[level], x0, y0, radius(level+1), radius(level))
+		}
+	}
+
+	if !c.Key.Hide {
+		g.Key(layout.KeyX, layout.KeyY, c.Key, c.Options)
+	}
+
+	g.End()
+}
+
+// wedges appends the wedges of nodes (whose values add up to sum) to
+// rings[level] and recursively those of their children. The nodes share
+// the angle span starting at phi. The function radius maps a level to the
+// inner radius of its ring.
+func (c *SunburstChart) wedges(g Graphics, rings [][]Wedgeinfo, nodes []SunburstNode, sum, phi, span float64, level int, radius func(int) int) {
+	if sum <= 0 {
+		return
+	}
+	lighten := c.Lighten
+	if lighten == 0 {
+		lighten = 0.25
+	}
+	_, fh, _ := g.FontMetrics(Font{})
+	for _, n := range nodes {
+		val := n.Value()
+		if val <= 0 {
+			continue
+		}
+		alpha := span * val / sum
+
+		var t string
+		if c.CatLabels {
+			t = n.Cat
+		}
+		if c.FmtVal != nil {
+			if t != "" {
+				t += " "
+			}
+			t += c.FmtVal(val, sum)
+		}
+		// Labels are drawn in the middle of the ring, see GenericRings.
+		ri, ro := radius(level), radius(level+1)
+		arc := alpha * float64(ri+ro) / 2
+		if ri == 0 {
+			arc = alpha * float64(ro) / 2
+		}
+		if t != "" && (arc < float64(g.TextLen(t, Font{})) || ro-ri < fh) {
+			t = ""
+		}
+
+		rings[level] = append(rings[level], Wedgeinfo{Phi: phi, Psi: phi + alpha, Text: t, Tp: "c",
+			Style: n.Style, Font: Font{}})
+
+		children := make([]SunburstNode, len(n.Children))
+		copy(children, n.Children)
+		for i := range children {
+			if children[i].Style.empty() {
+				children[i].Style = n.Style
+				if n.Style.FillColor != nil {
+					children[i].Style.FillColor = whiten(n.Style.FillColor, lighten)
+				}
+			}
+		}
+		c.wedges(g, rings, children, val, phi, alpha, level+1, radius)
+
+		phi += alpha
+	}
+}
+
+// whiten moves col the fraction f towards white.
+func whiten(col color.Color, f float64) color.NRGBA {
+	c := color.NRGBAModel.Convert(col).(color.NRGBA)
+	w := func(v uint8) uint8 { return v + uint8(f*float64(255-v)+0.5) }
+	return color.NRGBA{w(c.R), w(c.G), w(c.B), c.A}
+}
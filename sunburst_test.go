@@ -0,0 +1,70 @@
+package chart_test
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/vdobler/chart"
+	"github.com/vdobler/chart/txtg"
+)
+
+// ringRecorder records the wedges and radii of all rings plotted.
+type ringRecorder struct {
+	*txtg.TextGraphics
+	rings []string
+}
+
+func (r *ringRecorder) Rings(wedges []chart.Wedgeinfo, x, y, ro, ri int) {
+	s := fmt.Sprintf("%d-%d:", ri, ro)
+	for _, w := range wedges {
+		s += fmt.Sprintf(" %s %.2f..%.2f", w.Text, w.Phi/math.Pi, w.Psi/math.Pi)
+	}
+	r.rings = append(r.rings, s)
+}
+
+func TestSunburstAngles(t *testing.T) {
+	c := chart.SunburstChart{CatLabels: true}
+	c.Key.Hide = true
+	c.AddPath([]string{"A", "A1"}, 1)
+	c.AddPath([]string{"A", "A2"}, 3)
+	c.AddPath([]string{"B"}, 2)
+	c.AddPath([]string{"B"}, 2)
+	c.AddPath([]string{"C", "C1", "C11"}, 4)
+	c.AddPath([]string{"C", "C1", "C12"}, 4)
+
+	g := &ringRecorder{TextGraphics: txtg.New(100, 60)}
+	c.Plot(g)
+	// Outermost ring first. Angles in units of pi start at -pi, each child
+	// spans its share of its parent.
+	got := strings.Join(g.rings, "\n")
+	expected := "" +
+		"19-29: C11 0.00..0.50 C12 0.50..1.00\n" +
+		"9-19: A1 -1.00..-0.88 A2 -0.88..-0.50 C1 0.00..1.00\n" +
+		"0-9: A -1.00..-0.50 B -0.50..0.00 C 0.00..1.00"
+	if got != expected {
+		t.Errorf("got\n%s\nexpected\n%s", got, expected)
+	}
+}
+
+func TestSunburstAddPath(t *testing.T) {
+	// Values of categories with subcategories are kept in "(other)".
+	c := chart.SunburstChart{}
+	c.AddPath([]string{"A"}, 5)
+	c.AddPath([]string{"A", "B"}, 3)
+	c.AddPath([]string{"A"}, 1)
+	c.AddPath([]string{"A", "B", "C"}, 2)
+	got := ""
+	var dump func(n chart.SunburstNode, indent string)
+	dump = func(n chart.SunburstNode, indent string) {
+		got += fmt.Sprintf("%s%s=%g ", indent, n.Cat, n.Value())
+		for _, ch := range n.Children {
+			dump(ch, indent+"-")
+		}
+	}
+	dump(c.Data[0], "")
+	if expected := "A=11 -(other)=6 -B=5 --(other)=3 --C=2 "; got != expected {
+		t.Errorf("got %q, expected %q", got, expected)
+	}
+}
@@ -274,7 +274,7 @@ func (sg *SvgGraphics) Path(x, y []int, style chart.Style) {
 }
 
 func (sg *SvgGraphics) Wedge(x, y, ro, ri int, phi, psi float64, style chart.Style) {
-	panic("No Wedge() for SvgGraphics.")
+	sg.wedge(x, y, ro, ri, phi, psi, 0.4*float64(style.LineWidth), style)
 }
 
 func (sg *SvgGraphics) XAxis(xr chart.Range, ys, yms int, options chart.PlotOptions) {
@@ -356,76 +356,81 @@ func (sg *SvgGraphics) Bars(bars []chart.Barinfo, style chart.Style) {
 	chart.GenericBars(sg, bars, style)
 }
 
-func (sg *SvgGraphics) Rings(wedges []chart.Wedgeinfo, x, y, ro, ri int) {
-	for _, w := range wedges {
-		var s string
-		linecol := w.Style.LineColor
-		if linecol != nil {
-			s = fmt.Sprintf("stroke:%s; ", hexcol(linecol))
-			s += fmt.Sprintf("opacity: %s; ", alpha(linecol))
-		} else {
-			s = "stroke:%s; #808080; "
-		}
-		s += fmt.Sprintf("stroke-width: %d; ", w.Style.LineWidth)
-		var sf string
-		if w.Style.FillColor != nil {
-			sf = fmt.Sprintf("fill: %s; fill-opacity: %s", hexcol(w.Style.FillColor), alpha(w.Style.FillColor))
-		} else {
-			sf = "fill-opacity: 0"
-		}
+// wedge draws the wedge or ring segment from phi to psi with radii ro and ri
+// centered at (x,y) shrunk by p.
+func (sg *SvgGraphics) wedge(x, y, ro, ri int, phi, psi, p float64, style chart.Style) {
+	var s string
+	linecol := style.LineColor
+	if linecol != nil {
+		s = fmt.Sprintf("stroke:%s; ", hexcol(linecol))
+		s += fmt.Sprintf("opacity: %s; ", alpha(linecol))
+	} else {
+		s = "stroke:%s; #808080; "
+	}
+	s += fmt.Sprintf("stroke-width: %d; ", style.LineWidth)
+	var sf string
+	if style.FillColor != nil {
+		sf = fmt.Sprintf("fill: %s; fill-opacity: %s", hexcol(style.FillColor), alpha(style.FillColor))
+	} else {
+		sf = "fill-opacity: 0"
+	}
 
-		if math.Abs(w.Phi-w.Psi) >= 4*math.Pi {
-			sg.svg.Circle(x, y, ro, s+sf)
-			if ri > 0 {
-				sf = "fill: #ffffff; fill-opacity: 1"
-				sg.svg.Circle(x, y, ri, s+sf)
-			}
-			continue
+	if math.Abs(phi-psi) >= 4*math.Pi {
+		sg.svg.Circle(x, y, ro, s+sf)
+		if ri > 0 {
+			sf = "fill: #ffffff; fill-opacity: 1"
+			sg.svg.Circle(x, y, ri, s+sf)
 		}
+		return
+	}
 
-		var d string
-		p := 0.4 * float64(w.Style.LineWidth+w.Shift)
-		cphi, sphi := math.Cos(w.Phi), math.Sin(w.Phi)
-		cpsi, spsi := math.Cos(w.Psi), math.Sin(w.Psi)
-
-		if ri <= 0 {
-			// real wedge drawn as center -> outer radius -> arc -> closed to center
-			rf := float64(ro)
-			a := math.Sin((w.Psi - w.Phi) / 2)
-			dx, dy := p*math.Cos((w.Phi+w.Psi)/2)/a, p*math.Sin((w.Phi+w.Psi)/2)/a
-			d = fmt.Sprintf("M %d,%d ", x+int(dx+0.5), y+int(dy+0.5))
-
-			dx, dy = p*math.Cos(w.Phi+math.Pi/2), p*math.Sin(w.Phi+math.Pi/2)
-			d += fmt.Sprintf("L %d,%d ", int(rf*cphi+0.5+dx)+x, int(rf*sphi+0.5+dy)+y)
-
-			dx, dy = p*math.Cos(w.Psi-math.Pi/2), p*math.Sin(w.Psi-math.Pi/2)
-			if math.Abs(w.Phi-w.Psi)>math.Pi {
-				d += fmt.Sprintf("A %d,%d 0 1 1 %d,%d ", ro, ro, int(rf*cpsi+0.5+dx)+x, int(rf*spsi+0.5+dy)+y) } else {
-				d += fmt.Sprintf("A %d,%d 0 0 1 %d,%d ", ro, ro, int(rf*cpsi+0.5+dx)+x, int(rf*spsi+0.5+dy)+y) } 
-			d += fmt.Sprintf("z")
-		} else {
-			// ring drawn as inner radius -> outer radius -> outer arc -> inner radius -> inner arc
-			rof, rif := float64(ro), float64(ri)
-			dx, dy := p*math.Cos(w.Phi+math.Pi/2), p*math.Sin(w.Phi+math.Pi/2)
-			a, b := int(rif*cphi+0.5+dx)+x, int(rif*sphi+0.5+dy)+y
-			d = fmt.Sprintf("M %d,%d ", a, b)
-			d += fmt.Sprintf("L %d,%d ", int(rof*cphi+0.5+dx)+x, int(rof*sphi+0.5+dy)+y)
-
-			dx, dy = p*math.Cos(w.Psi-math.Pi/2), p*math.Sin(w.Psi-math.Pi/2)
-			if math.Abs(w.Phi-w.Psi)>math.Pi {
-				d += fmt.Sprintf("A %d,%d 0 1 1 %d,%d ", ro, ro, int(rof*cpsi+0.5+dx)+x, int(rof*spsi+0.5+dy)+y) } else {
-				d += fmt.Sprintf("A %d,%d 0 0 1 %d,%d ", ro, ro, int(rof*cpsi+0.5+dx)+x, int(rof*spsi+0.5+dy)+y) } 
-			d += fmt.Sprintf("L %d,%d ", int(rif*cpsi+0.5+dx)+x, int(rif*spsi+0.5+dy)+y)
-			if math.Abs(w.Phi-w.Psi)>math.Pi {
-				d += fmt.Sprintf("A %d,%d 0 1 0 %d,%d ", ri, ri, a, b) } else {
-				d += fmt.Sprintf("A %d,%d 0 0 0 %d,%d ", ri, ri, a, b) }
-			d += fmt.Sprintf("z")
+	var d string
+	cphi, sphi := math.Cos(phi), math.Sin(phi)
+	cpsi, spsi := math.Cos(psi), math.Sin(psi)
 
-		}
+	if ri <= 0 {
+		// real wedge drawn as center -> outer radius -> arc -> closed to center
+		rf := float64(ro)
+		a := math.Sin((psi - phi) / 2)
+		dx, dy := p*math.Cos((phi+psi)/2)/a, p*math.Sin((phi+psi)/2)/a
+		d = fmt.Sprintf("M %d,%d ", x+int(dx+0.5), y+int(dy+0.5))
 
-		sg.svg.Path(d, s+sf)
+		dx, dy = p*math.Cos(phi+math.Pi/2), p*math.Sin(phi+math.Pi/2)
+		d += fmt.Sprintf("L %d,%d ", int(rf*cphi+0.5+dx)+x, int(rf*sphi+0.5+dy)+y)
+
+		dx, dy = p*math.Cos(psi-math.Pi/2), p*math.Sin(psi-math.Pi/2)
+		if math.Abs(phi-psi)>math.Pi {
+			d += fmt.Sprintf("A %d,%d 0 1 1 %d,%d ", ro, ro, int(rf*cpsi+0.5+dx)+x, int(rf*spsi+0.5+dy)+y) } else {
+			d += fmt.Sprintf("A %d,%d 0 0 1 %d,%d ", ro, ro, int(rf*cpsi+0.5+dx)+x, int(rf*spsi+0.5+dy)+y) } 
+		d += fmt.Sprintf("z")
+	} else {
+		// ring drawn as inner radius -> outer radius -> outer arc -> inner radius -> inner arc
+		rof, rif := float64(ro), float64(ri)
+		dx, dy := p*math.Cos(phi+math.Pi/2), p*math.Sin(phi+math.Pi/2)
+		a, b := int(rif*cphi+0.5+dx)+x, int(rif*sphi+0.5+dy)+y
+		d = fmt.Sprintf("M %d,%d ", a, b)
+		d += fmt.Sprintf("L %d,%d ", int(rof*cphi+0.5+dx)+x, int(rof*sphi+0.5+dy)+y)
+
+		dx, dy = p*math.Cos(psi-math.Pi/2), p*math.Sin(psi-math.Pi/2)
+		if math.Abs(phi-psi)>math.Pi {
+			d += fmt.Sprintf("A %d,%d 0 1 1 %d,%d ", ro, ro, int(rof*cpsi+0.5+dx)+x, int(rof*spsi+0.5+dy)+y) } else {
+			d += fmt.Sprintf("A %d,%d 0 0 1 %d,%d ", ro, ro, int(rof*cpsi+0.5+dx)+x, int(rof*spsi+0.5+dy)+y) } 
+		d += fmt.Sprintf("L %d,%d ", int(rif*cpsi+0.5+dx)+x, int(rif*spsi+0.5+dy)+y)
+		if math.Abs(phi-psi)>math.Pi {
+			d += fmt.Sprintf("A %d,%d 0 1 0 %d,%d ", ri, ri, a, b) } else {
+			d += fmt.Sprintf("A %d,%d 0 0 0 %d,%d ", ri, ri, a, b) }
+		d += fmt.Sprintf("z")
+
+	}
+
+	sg.svg.Path(d, s+sf)
+}
+
+func (sg *SvgGraphics) Rings(wedges []chart.Wedgeinfo, x, y, ro, ri int) {
+	for _, w := range wedges {
+		sg.wedge(x, y, ro, ri, w.Phi, w.Psi, 0.4*float64(w.Style.LineWidth+w.Shift), w.Style)
 
-		if w.Text != "" {
+		if w.Text != "" && w.Tp != "o" {
 			_, fh, _ := sg.FontMetrics(w.Font)
 			alpha := (w.Phi + w.Psi) / 2
 			var rt int
@@ -442,6 +447,7 @@ func (sg *SvgGraphics) Rings(wedges []chart.Wedgeinfo, x, y, ro, ri int) {
 			sg.Text(tx, ty, w.Text, "cc", 0, w.Font)
 		}
 	}
+	chart.GenericPieLabels(sg, wedges, x, y, ro, 1)
 }
 
 func hexcol(col color.Color) string {
@@ -0,0 +1,183 @@
+package chart
+
+import (
+	"math"
+	"sort"
+)
+
+// TDigest is a streaming sketch of a distribution (Dunning's merging
+// t-digest): Samples are clustered into a bounded number of centroids
+// which are small near the tails so that extreme quantiles stay accurate.
+// It allows quantiles of data sets which do not fit into memory and can
+// be merged with other digests. The zero value is an empty digest with
+// compression 100.
+type TDigest struct {
+	Compression float64 // roughly the number of centroids kept, 0 is 100
+
+	means, weights []float64 // the centroids, sorted by mean
+	bufx, bufw     []float64 // unmerged samples
+	count          float64
+	min, max       float64
+}
+
+// NewTDigest returns an empty digest with the given compression.
+func NewTDigest(compression float64) *TDigest {
+	return &TDigest{Compression: compression}
+}
+
+func (d *TDigest) compression() float64 {
+	if d.Compression <= 0 {
+		return 100
+	}
+	return d.Compression
+}
+
+// Add adds sample x with weight w (use 1 for plain samples).
+func (d *TDigest) Add(x, w float64) {
+	if w <= 0 || math.IsNaN(x) || math.IsNaN(w) {
+		return
+	}
+	if d.count == 0 {
+		d.min, d.max = x, x
+	} else {
+		d.min, d.max = math.Min(d.min, x), math.Max(d.max, x)
+	}
+	d.count += w
+	d.bufx = append(d.bufx, x)
+	d.bufw = append(d.bufw, w)
+	if float64(len(d.bufx)) >= 5*d.compression() {
+		d.compress()
+	}
+}
+
+// AddData adds all samples in data with weight 1.
+func (d *TDigest) AddData(data []float64) {
+	for _, x := range data {
+		d.Add(x, 1)
+	}
+}
+
+// Merge adds all samples summarized by o to d.
+func (d *TDigest) Merge(o *TDigest) {
+	if o.count == 0 {
+		return
+	}
+	o.compress()
+	min, max, count := o.min, o.max, d.count
+	if count > 0 {
+		min, max = math.Min(d.min, min), math.Max(d.max, max)
+	}
+	for i, m := range o.means {
+		d.Add(m, o.weights[i])
+	}
+	d.min, d.max = min, max
+}
+
+// compress merges the buffered samples into the centroids. A centroid
+// may grow as long as it spans at most one unit of the scale function
+// k(q) = compression/(2*pi) * asin(2q-1).
+func (d *TDigest) compress() {
+	if len(d.bufx) == 0 {
+		return
+	}
+	x := append(d.means, d.bufx...)
+	w := append(d.weights, d.bufw...)
+	sort.Sort(weightedSamples{x, w})
+	d.bufx, d.bufw = d.bufx[:0], d.bufw[:0]
+
+	delta := d.compression()
+	k := func(q float64) float64 { return delta / (2 * math.Pi) * math.Asin(2*q-1) }
+	kinv := func(k float64) float64 {
+		if k >= delta/4 {
+			return 1
+		}
+		return (math.Sin(2*math.Pi*k/delta) + 1) / 2
+	}
+
+	means, weights := []float64{x[0]}, []float64{w[0]}
+	sofar := 0.0 // weight of the completed centroids
+	limit := d.count * kinv(k(0)+1)
+	for i := 1; i < len(x); i++ {
+		j := len(means) - 1
+		if sofar+weights[j]+w[i] <= limit {
+			weights[j] += w[i]
+			means[j] += (x[i] - means[j]) * w[i] / weights[j]
+			continue
+		}
+		sofar += weights[j]
+		limit = d.count * kinv(k(sofar/d.count)+1)
+		means, weights = append(means, x[i]), append(weights, w[i])
+	}
+	d.means, d.weights = means, weights
+}
+
+// Count returns the total weight of all samples.
+func (d *TDigest) Count() float64 { return d.count }
+
+// Min returns the smallest sample.
+func (d *TDigest) Min() float64 { return d.min }
+
+// Max returns the largest sample.
+func (d *TDigest) Max() float64 { return d.max }
+
+// Centroids returns the means and weights of the centroids of d.
+func (d *TDigest) Centroids() (means, weights []float64) {
+	d.compress()
+	means, weights = make([]float64, len(d.means)), make([]float64, len(d.weights))
+	copy(means, d.means)
+	copy(weights, d.weights)
+	return means, weights
+}
+
+// knots returns the points of the piecewise linear approximation of the
+// cumulative distribution: The mean of each centroid is placed at the
+// middle of its cumulative weight; min and max at 0 and Count.
+func (d *TDigest) knots() (x, c []float64) {
+	d.compress()
+	x, c = []float64{d.min}, []float64{0}
+	cum := 0.0
+	for i, m := range d.means {
+		x, c = append(x, m), append(c, cum+d.weights[i]/2)
+		cum += d.weights[i]
+	}
+	return append(x, d.max), append(c, d.count)
+}
+
+// Quantile returns the estimated q quantile (0 <= q <= 1) of the samples.
+func (d *TDigest) Quantile(q float64) float64 {
+	if d.count == 0 {
+		return math.NaN()
+	}
+	if q <= 0 {
+		return d.min
+	}
+	if q >= 1 {
+		return d.max
+	}
+	x, c := d.knots()
+	t := q * d.count
+	i := sort.SearchFloat64s(c, t) // c[i-1] < t <= c[i]
+	if i == 0 {
+		return x[0]
+	}
+	return x[i-1] + (x[i]-x[i-1])*(t-c[i-1])/(c[i]-c[i-1])
+}
+
+// CDF returns the estimated fraction of samples <= v.
+func (d *TDigest) CDF(v float64) float64 {
+	if d.count == 0 {
+		return math.NaN()
+	}
+	if v < d.min {
+		return 0
+	}
+	if v >= d.max {
+		return 1
+	}
+	x, c := d.knots()
+	i := sort.SearchFloat64s(x, v) // x[i-1] < v <= x[i]
+	if i == 0 {
+		return 0
+	}
+	return (c[i-1] + (c[i]-c[i-1])*(v-x[i-1])/(x[i]-x[i-1])) / d.count
+}
@@ -0,0 +1,45 @@
+package chart
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestTDigest(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var d, d1, d2 TDigest
+	for i := 0; i < 100000; i++ {
+		x := rng.Float64()
+		d.Add(x, 1)
+		if i%2 == 0 {
+			d1.Add(x, 1)
+		} else {
+			d2.Add(x, 1)
+		}
+	}
+	d1.Merge(&d2)
+	for _, dig := range []*TDigest{&d, &d1} {
+		if dig.Count() != 100000 {
+			t.Errorf("Count: Got %g", dig.Count())
+		}
+		if means, _ := dig.Centroids(); len(means) > 200 {
+			t.Errorf("Too many centroids: %d", len(means))
+		}
+		for _, q := range []float64{0.001, 0.01, 0.25, 0.5, 0.75, 0.99, 0.999} {
+			tol := 0.01
+			if q < 0.05 || q > 0.95 {
+				tol = 0.001
+			}
+			if got := dig.Quantile(q); math.Abs(got-q) > tol {
+				t.Errorf("Quantile(%g): Got %g", q, got)
+			}
+			if got := dig.CDF(q); math.Abs(got-q) > tol {
+				t.Errorf("CDF(%g): Got %g", q, got)
+			}
+		}
+	}
+	if d.Quantile(0) != d.Min() || d.Quantile(1) != d.Max() {
+		t.Errorf("Bad extremes")
+	}
+}
@@ -111,8 +111,7 @@ type Month struct {
 
 func (m Month) Seconds() int64 { return 60 * 60 * 24 * 365.25 / 12 * int64(m.Num) }
 func (m Month) RoundDown(t time.Time) time.Time {
-	return time.Date(t.Year(), time.Month(m.Num*(int(t.Month())-1)/m.Num+1),
-		1, 0, 0, 0, 0, t.Location())
+	return roundDownMonths(t, m.Num, time.January)
 }
 func (m Month) String() string { return fmt.Sprintf("%d month(s)", m.Num) }
 func (m Month) Format(t time.Time) string {
@@ -126,6 +125,112 @@ func (m Month) Format(t time.Time) string {
 }
 func (m Month) Period() bool { return true }
 
+// roundDownMonths rounds t down to the first day of a block of n months
+// where blocks are aligned to month start.
+func roundDownMonths(t time.Time, n int, start time.Month) time.Time {
+	if start < time.January || start > time.December {
+		start = time.January
+	}
+	rel := 12*t.Year() + int(t.Month()) - int(start)
+	if rel < 0 {
+		rel -= n - 1
+	}
+	rel = n*(rel/n) + int(start) - 1
+	return time.Date(rel/12, time.Month(rel%12+1), 1, 0, 0, 0, 0, t.Location())
+}
+
+// fiscalYear returns the fiscal year t belongs to if the fiscal year starts
+// in month start. Fiscal years are named after the calendar year they end in.
+func fiscalYear(t time.Time, start time.Month) int {
+	if start > time.January && t.Month() >= start {
+		return t.Year() + 1
+	}
+	return t.Year()
+}
+
+// fiscal reports whether start denotes a fiscal year which differs from
+// the calendar year.
+func fiscal(start time.Month) bool {
+	return start > time.January && start <= time.December
+}
+
+// Quarter of a (fiscal) year. Start is the first month of the fiscal
+// year; the zero value and January both denote calendar quarters.
+type Quarter struct {
+	Num   int
+	Start time.Month
+}
+
+func (q Quarter) Seconds() int64 { return 60 * 60 * 24 * 365.25 / 4 * int64(q.Num) }
+func (q Quarter) RoundDown(t time.Time) time.Time {
+	return roundDownMonths(t, 3*q.Num, q.Start)
+}
+func (q Quarter) String() string { return fmt.Sprintf("%d quarter(s)", q.Num) }
+func (q Quarter) Format(t time.Time) string {
+	if !fiscal(q.Start) {
+		return fmt.Sprintf("Q%d %d", (int(t.Month())-1)/3+1, t.Year())
+	}
+	n := (int(t.Month())-int(q.Start)+12)%12/3 + 1
+	return fmt.Sprintf("Q%d FY%d", n, fiscalYear(t, q.Start))
+}
+func (q Quarter) Period() bool { return true }
+
+// HalfYear of a (fiscal) year. Start is the first month of the fiscal
+// year; the zero value and January both denote calendar half years.
+type HalfYear struct {
+	Num   int
+	Start time.Month
+}
+
+func (h HalfYear) Seconds() int64 { return 60 * 60 * 24 * 365.25 / 2 * int64(h.Num) }
+func (h HalfYear) RoundDown(t time.Time) time.Time {
+	return roundDownMonths(t, 6*h.Num, h.Start)
+}
+func (h HalfYear) String() string { return fmt.Sprintf("%d half year(s)", h.Num) }
+func (h HalfYear) Format(t time.Time) string {
+	if !fiscal(h.Start) {
+		return fmt.Sprintf("H%d %d", (int(t.Month())-1)/6+1, t.Year())
+	}
+	n := (int(t.Month())-int(h.Start)+12)%12/6 + 1
+	return fmt.Sprintf("H%d FY%d", n, fiscalYear(t, h.Start))
+}
+func (h HalfYear) Period() bool { return true }
+
+// FiscalYear is a year starting in month Start. A zero Start or January
+// yields calendar years labeled like Year but prefixed with "FY".
+type FiscalYear struct {
+	Num   int
+	Start time.Month
+}
+
+func (y FiscalYear) Seconds() int64 { return 60 * 60 * 24 * 365.25 * int64(y.Num) }
+func (y FiscalYear) RoundDown(t time.Time) time.Time {
+	return roundDownMonths(t, 12*y.Num, y.Start)
+}
+func (y FiscalYear) String() string { return fmt.Sprintf("%d fiscal year(s)", y.Num) }
+func (y FiscalYear) Format(t time.Time) string {
+	return fmt.Sprintf("FY%d", fiscalYear(t, y.Start))
+}
+func (y FiscalYear) Period() bool { return true }
+
+// Decade
+type Decade struct {
+	Num int
+}
+
+func (d Decade) Seconds() int64 { return 60 * 60 * 24 * 365.25 * 10 * int64(d.Num) }
+func (d Decade) RoundDown(t time.Time) time.Time {
+	n := 10 * d.Num
+	y := t.Year()
+	if y < 0 {
+		y -= n - 1
+	}
+	return time.Date(n*(y/n), 1, 1, 0, 0, 0, 0, t.Location())
+}
+func (d Decade) String() string            { return fmt.Sprintf("%d decade(s)", d.Num) }
+func (d Decade) Format(t time.Time) string { return fmt.Sprintf("%ds", 10*(t.Year()/10)) }
+func (d Decade) Period() bool              { return true }
+
 // Year
 type Year struct {
 	Num int
@@ -162,8 +267,33 @@ var Delta []TimeDelta = []TimeDelta{
 	Minute{1}, Minute{5}, Minute{15},
 	Hour{1}, Hour{6},
 	Day{1}, Week{1},
-	Month{1}, Month{3}, Month{6},
-	Year{1}, Year{10}, Year{100},
+	Month{1}, Quarter{1, 0}, HalfYear{1, 0},
+	Year{1}, Decade{1}, Year{100},
+}
+
+// FiscalTimeDelta returns d adopted to a fiscal year starting in month start:
+// Quarters, half years and single years are turned into their fiscal
+// counterparts, all other deltas are returned unchanged.
+func FiscalTimeDelta(d TimeDelta, start time.Month) TimeDelta {
+	if !fiscal(start) {
+		return d
+	}
+	switch t := d.(type) {
+	case Quarter:
+		t.Start = start
+		return t
+	case HalfYear:
+		t.Start = start
+		return t
+	case FiscalYear:
+		t.Start = start
+		return t
+	case Year:
+		if t.Num == 1 {
+			return FiscalYear{1, start}
+		}
+	}
+	return d
 }
 
 // RoundUp will round tp up to next "full" d.
@@ -204,6 +334,7 @@ func RoundDown(t time.Time, d TimeDelta) time.Time {
 	return td
 }
 
+// NextTimeDelta returns the next larger time delta from Delta.
 func NextTimeDelta(d TimeDelta) TimeDelta {
 	var i = 0
 	sec := d.Seconds()
@@ -216,6 +347,8 @@ func NextTimeDelta(d TimeDelta) TimeDelta {
 	return Delta[len(Delta)-1]
 }
 
+// MatchingTimeDelta returns the first time delta d from Delta (ignoring the
+// smallest) for which fac*d is at least delta.
 func MatchingTimeDelta(delta float64, fac float64) TimeDelta {
 	var i = 0
 	for i+1 < len(Delta) && delta > fac*float64(Delta[i+1].Seconds()) {
@@ -25,6 +25,18 @@ func TestRoundDown(t *testing.T) {
 
 		{"2011-07-04 16:43:23 CEST", "2011-01-01 00:00:00 CET", Year{1}},
 		{"2011-07-04 16:43:23 CEST", "2010-01-01 00:00:00 CET", Year{10}},
+
+		// Quarters, half years and decades
+		{"2011-08-04 16:43:23 CEST", "2011-07-01 00:00:00 CEST", Month{3}},
+		{"2011-08-04 16:43:23 CEST", "2011-07-01 00:00:00 CEST", Quarter{1, 0}},
+		{"2011-08-04 16:43:23 CEST", "2011-07-01 00:00:00 CEST", HalfYear{1, 0}},
+		{"2011-08-04 16:43:23 CEST", "2010-01-01 00:00:00 CET", Decade{1}},
+
+		// Fiscal years starting in October
+		{"2011-08-04 16:43:23 CEST", "2011-07-01 00:00:00 CEST", Quarter{1, time.October}},
+		{"2011-11-04 16:43:23 CET", "2011-10-01 00:00:00 CEST", Quarter{1, time.October}},
+		{"2011-02-04 16:43:23 CET", "2010-10-01 00:00:00 CEST", HalfYear{1, time.October}},
+		{"2011-08-04 16:43:23 CEST", "2010-10-01 00:00:00 CEST", FiscalYear{1, time.October}},
 	}
 
 	for k, sample := range samples {
@@ -44,3 +56,25 @@ func TestRoundDown(t *testing.T) {
 	}
 
 }
+
+func TestFiscalFormat(t *testing.T) {
+	date := time.Date(2011, time.November, 4, 12, 0, 0, 0, time.UTC)
+	samples := []struct {
+		delta    TimeDelta
+		expected string
+	}{
+		{Quarter{1, 0}, "Q4 2011"},
+		{Quarter{1, time.October}, "Q1 FY2012"},
+		{HalfYear{1, 0}, "H2 2011"},
+		{HalfYear{1, time.April}, "H2 FY2012"},
+		{FiscalYear{1, time.July}, "FY2012"},
+		{Decade{1}, "2010s"},
+		{FiscalTimeDelta(Year{1}, time.October), "FY2012"},
+		{FiscalTimeDelta(Quarter{1, 0}, time.January), "Q4 2011"},
+	}
+	for k, sample := range samples {
+		if got := sample.delta.Format(date); got != sample.expected {
+			t.Errorf("%d. %s: got %q, want %q", k, sample.delta, got, sample.expected)
+		}
+	}
+}
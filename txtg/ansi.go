@@ -0,0 +1,163 @@
+package txtg
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"strings"
+)
+
+// ColorMode determines how colors are rendered to text.
+type ColorMode int
+
+const (
+	NoColor   ColorMode = iota // plain text without any escape sequences
+	ANSI16                     // the 16 standard ANSI colors
+	ANSI256                    // the xterm 256 color palette
+	TrueColor                  // 24 bit colors
+)
+
+// AutoColor returns the color mode suitable for output to f: NoColor if f is
+// not a terminal or the NO_COLOR environment variable is set, otherwise
+// the best mode advertised by the COLORTERM and TERM variables.
+func AutoColor(f *os.File) ColorMode {
+	if os.Getenv("NO_COLOR") != "" {
+		return NoColor
+	}
+	if fi, err := f.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return NoColor
+	}
+	term := os.Getenv("TERM")
+	switch ct := os.Getenv("COLORTERM"); {
+	case term == "dumb" || term == "":
+		return NoColor
+	case ct == "truecolor" || ct == "24bit":
+		return TrueColor
+	case strings.Contains(term, "256color"):
+		return ANSI256
+	}
+	return ANSI16
+}
+
+// ansi16 is the (xterm) palette of the 16 standard ANSI colors.
+var ansi16 = [16][3]int{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the intensities of the 6x6x6 color cube of the 256 color palette.
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// rgb returns the non-alpha-premultiplied 8 bit components of c and
+// whether c is visible at all.
+func rgb(c color.Color) (r, g, b int, ok bool) {
+	if c == nil {
+		return 0, 0, 0, false
+	}
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return int(n.R), int(n.G), int(n.B), n.A != 0
+}
+
+func dist2(r, g, b int, p [3]int) int {
+	dr, dg, db := r-p[0], g-p[1], b-p[2]
+	return dr*dr + dg*dg + db*db
+}
+
+// nearest returns the index of the level in levels closest to v.
+func nearest(v int, levels []int) int {
+	best := 0
+	for i, l := range levels {
+		if abs(v-l) < abs(v-levels[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// sgr returns the parameters of the SGR escape sequence selecting c
+// as foreground (bg==false) or background color in mode.
+func sgr(c color.Color, bg bool, mode ColorMode) string {
+	r, g, b, ok := rgb(c)
+	if !ok {
+		return ""
+	}
+	base := 38
+	if bg {
+		base = 48
+	}
+	switch mode {
+	case TrueColor:
+		return fmt.Sprintf("%d;2;%d;%d;%d", base, r, g, b)
+	case ANSI256:
+		levels := cubeLevels[:]
+		ri, gi, bi := nearest(r, levels), nearest(g, levels), nearest(b, levels)
+		n := 16 + 36*ri + 6*gi + bi
+		best := dist2(r, g, b, [3]int{levels[ri], levels[gi], levels[bi]})
+		// Grayscale ramp 232 to 255 covers 8, 18, ..., 238.
+		gray := min(23, max(0, ((r+g+b)/3-3)/10))
+		if v := 8 + 10*gray; dist2(r, g, b, [3]int{v, v, v}) < best {
+			n = 232 + gray
+		}
+		return fmt.Sprintf("%d;5;%d", base, n)
+	}
+	best := 0
+	for i, p := range ansi16 {
+		if dist2(r, g, b, p) < dist2(r, g, b, ansi16[best]) {
+			best = i
+		}
+	}
+	n := 30 + best
+	if best >= 8 {
+		n = 90 + best - 8
+	}
+	if bg {
+		n += 10
+	}
+	return fmt.Sprint(n)
+}
+
+// ANSI converts the buffer to a string with ANSI escape sequences for
+// the colors of the cells. Escape sequences are emitted only when the
+// color changes and colors are reset at the end of each line.
+// For mode NoColor the result is the same as String.
+func (tb *TextBuf) ANSI(mode ColorMode) string {
+	if mode == NoColor || tb.Fg == nil || tb.Bg == nil {
+		return tb.String()
+	}
+	var buf strings.Builder
+	cur := ""
+	for i, r := range tb.Buf {
+		if r == '\n' {
+			if cur != "" {
+				buf.WriteString("\x1b[0m")
+				cur = ""
+			}
+			buf.WriteRune(r)
+			continue
+		}
+		var p []string
+		if s := sgr(tb.Fg[i], false, mode); s != "" {
+			p = append(p, s)
+		}
+		if s := sgr(tb.Bg[i], true, mode); s != "" {
+			p = append(p, s)
+		}
+		seq := strings.Join(p, ";")
+		if seq != cur {
+			if cur != "" {
+				buf.WriteString("\x1b[0m")
+			}
+			if seq != "" {
+				buf.WriteString("\x1b[" + seq + "m")
+			}
+			cur = seq
+		}
+		buf.WriteRune(r)
+	}
+	if cur != "" {
+		buf.WriteString("\x1b[0m")
+	}
+	return buf.String()
+}
@@ -0,0 +1,63 @@
+package txtg
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestSGR(t *testing.T) {
+	red := color.NRGBA{0xff, 0, 0, 0xff}
+	gray := color.NRGBA{0x80, 0x80, 0x80, 0xff}
+	for i, tc := range []struct {
+		c        color.Color
+		bg       bool
+		mode     ColorMode
+		expected string
+	}{
+		{red, false, TrueColor, "38;2;255;0;0"},
+		{red, true, TrueColor, "48;2;255;0;0"},
+		{color.NRGBA{0, 0, 0xff, 0x80}, false, TrueColor, "38;2;0;0;255"}, // alpha is ignored
+		{red, false, ANSI256, "38;5;196"},                                 // color cube
+		{gray, true, ANSI256, "48;5;244"},                                 // grayscale ramp
+		{color.NRGBA{95, 135, 175, 0xff}, false, ANSI256, "38;5;67"},
+		{red, false, ANSI16, "91"},
+		{red, true, ANSI16, "101"},
+		{color.Black, false, ANSI16, "30"},
+		{color.Black, true, ANSI16, "40"},
+		{gray, false, ANSI16, "90"},
+		{color.NRGBA{0x20, 0xc0, 0x20, 0xff}, false, ANSI16, "32"},
+		{nil, false, TrueColor, ""},
+		{color.Transparent, true, ANSI16, ""},
+	} {
+		if got := sgr(tc.c, tc.bg, tc.mode); got != tc.expected {
+			t.Errorf("%d: sgr(%v, %t, %d) = %q, expected %q", i, tc.c, tc.bg, tc.mode, got, tc.expected)
+		}
+	}
+}
+
+func TestTextBufANSI(t *testing.T) {
+	red, blue := color.NRGBA{0xff, 0, 0, 0xff}, color.NRGBA{0, 0, 0xee, 0xff}
+	tb := NewTextBuf(4, 2)
+	tb.SetColor(red, nil)
+	tb.Put(0, 0, 'a')
+	tb.Put(1, 0, 'b') // same color: no new sequence
+	tb.SetColor(red, blue)
+	tb.Put(2, 0, 'c') // foreground and background merged into one sequence
+	tb.SetColor(red, nil)
+	tb.Put(3, 1, 'd') // color does not continue across lines
+	tb.SetColor(nil, nil)
+	tb.Put(0, 1, 'e')
+
+	for i, tc := range []struct {
+		mode     ColorMode
+		expected string
+	}{
+		{NoColor, "abc \ne  d\n"},
+		{ANSI16, "\x1b[91mab\x1b[0m\x1b[91;44mc\x1b[0m \ne  \x1b[91md\x1b[0m\n"},
+		{TrueColor, "\x1b[38;2;255;0;0mab\x1b[0m\x1b[38;2;255;0;0;48;2;0;0;238mc\x1b[0m \ne  \x1b[38;2;255;0;0md\x1b[0m\n"},
+	} {
+		if got := tb.ANSI(tc.mode); got != tc.expected {
+			t.Errorf("%d: got %q, expected %q", i, got, tc.expected)
+		}
+	}
+}
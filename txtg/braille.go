@@ -0,0 +1,338 @@
+package txtg
+
+import (
+	"github.com/vdobler/chart"
+	"image/color"
+	"math"
+)
+
+// Dots per character cell of a BrailleGraphics.
+const (
+	DotsX = 2
+	DotsY = 4
+)
+
+// brailleBit maps a dot (x,y) within a cell to its bit in the Unicode
+// Braille pattern U+2800 + bits.
+var brailleBit = [DotsX][DotsY]rune{{0x01, 0x02, 0x04, 0x40}, {0x08, 0x10, 0x20, 0x80}}
+
+// Block elements: lower eighths (index 1 to 8) and shades (light to full).
+var (
+	lowerBlocks = []rune{' ', '▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+	shades      = []rune{'░', '▒', '▓', '█'}
+)
+
+// BrailleGraphics is a text driver with sub-character resolution: Each
+// character cell provides DotsX x DotsY pixels. Lines are drawn with Unicode
+// Braille patterns, filled rectangles (bars, histograms) with block elements
+// and shades. Axis, tics, labels and symbols are plain text as in TextGraphics.
+// All coordinates are in pixel (dot) units.
+type BrailleGraphics struct {
+	Color ColorMode // colors in output of String, use AutoColor to detect terminal capabilities
+
+	txt  *TextGraphics // text layer: axis, labels, symbols and blocks
+	dots []rune        // braille bits of each character cell
+	w, h int           // width and height in characters
+}
+
+// NewBraille creates a BrailleGraphics of w x h characters, i.e. of
+// DotsX*w x DotsY*h pixels.
+func NewBraille(w, h int) *BrailleGraphics {
+	return &BrailleGraphics{txt: New(w, h), dots: make([]rune, w*h), w: w, h: h}
+}
+
+func (g *BrailleGraphics) Options() chart.PlotOptions {
+	return nil
+}
+
+func (g *BrailleGraphics) Begin() {
+	g.txt.Begin()
+	g.dots = make([]rune, g.w*g.h)
+}
+
+func (g *BrailleGraphics) End()                            {}
+func (bg *BrailleGraphics) Background() (r, g, b, a uint8) { return 255, 255, 255, 255 }
+func (g *BrailleGraphics) Dimensions() (int, int) {
+	return DotsX * g.w, DotsY * g.h
+}
+func (g *BrailleGraphics) FontMetrics(font chart.Font) (fw float32, fh int, mono bool) {
+	return DotsX, DotsY, true
+}
+
+func (g *BrailleGraphics) TextLen(t string, font chart.Font) int {
+	return DotsX * StrLen(t)
+}
+
+// dot sets the pixel (x,y) in the current color of the text buffer.
+func (g *BrailleGraphics) dot(x, y int) {
+	if x < 0 || y < 0 || x >= DotsX*g.w || y >= DotsY*g.h {
+		return
+	}
+	cx, cy := x/DotsX, y/DotsY
+	g.dots[cy*g.w+cx] |= brailleBit[x%DotsX][y%DotsY]
+	tb := g.txt.tb
+	if i := cy*(g.w+1) + cx; tb.Buf[i] == ' ' {
+		tb.Fg[i] = tb.fg
+	}
+}
+
+// block puts r into the character cell (cx,cy) and removes any dots there.
+func (g *BrailleGraphics) block(cx, cy int, r rune) {
+	if cx < 0 || cy < 0 || cx >= g.w || cy >= g.h {
+		return
+	}
+	g.txt.tb.Put(cx, cy, r)
+	g.dots[cy*g.w+cx] = 0
+}
+
+func (g *BrailleGraphics) Line(x0, y0, x1, y1 int, style chart.Style) {
+	g.txt.tb.SetColor(lineColor(style), nil)
+	defer g.txt.tb.SetColor(nil, nil)
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := sign(x1-x0), sign(y1-y0)
+	err, e2 := dx+dy, 0
+	for {
+		g.dot(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 = 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+func (g *BrailleGraphics) Path(x, y []int, style chart.Style) {
+	chart.GenericPath(g, x, y, style)
+}
+
+// Wedge fills cells completely inside the wedge with the shade of the fill
+// color and the remaining covered pixels with dots. Unfilled wedges are
+// outlined only.
+func (g *BrailleGraphics) Wedge(x, y, ro, ri int, phi, psi float64, style chart.Style) {
+	if style.FillColor == nil {
+		chart.GenericWedge(g, x, y, ro, ri, phi, psi, 1, style)
+		return
+	}
+
+	full := math.Abs(phi-psi) >= 4*math.Pi
+	phi, psi = normAngle(phi), normAngle(psi)
+	inside := func(px, py int) bool {
+		dx, dy := float64(px-x), float64(py-y)
+		r := math.Hypot(dx, dy)
+		if r > float64(ro) || r < float64(ri) {
+			return false
+		}
+		if full {
+			return true
+		}
+		a := normAngle(math.Atan2(dy, dx))
+		if phi <= psi {
+			return a >= phi && a <= psi
+		}
+		return a >= phi || a <= psi
+	}
+
+	shade := shadeOf(style.FillColor)
+	g.txt.tb.SetColor(style.FillColor, nil)
+	defer g.txt.tb.SetColor(nil, nil)
+	for cy := (y - ro) / DotsY; cy <= (y+ro)/DotsY; cy++ {
+		for cx := (x - ro) / DotsX; cx <= (x+ro)/DotsX; cx++ {
+			n := 0
+			for i := 0; i < DotsX; i++ {
+				for j := 0; j < DotsY; j++ {
+					if inside(cx*DotsX+i, cy*DotsY+j) {
+						n++
+					}
+				}
+			}
+			switch {
+			case n == DotsX*DotsY && shade < 0:
+				g.block(cx, cy, ' ')
+			case n == DotsX*DotsY:
+				g.block(cx, cy, shades[shade])
+			case n > 0 && shade >= 0:
+				for i := 0; i < DotsX; i++ {
+					for j := 0; j < DotsY; j++ {
+						if inside(cx*DotsX+i, cy*DotsY+j) {
+							g.dot(cx*DotsX+i, cy*DotsY+j)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// normAngle maps a to [0,2pi).
+func normAngle(a float64) float64 {
+	a = math.Mod(a, 2*math.Pi)
+	if a < 0 {
+		a += 2 * math.Pi
+	}
+	return a
+}
+
+func (g *BrailleGraphics) Text(x, y int, t string, align string, rot int, font chart.Font) {
+	if len(align) == 2 && align[0] == 'b' {
+		y--
+	}
+	g.txt.Text(x/DotsX, y/DotsY, t, align, rot, font)
+}
+
+// Rect draws the rectangle: Completely covered cells are filled with a shade
+// determined by the lightness of the fill color, partially covered cells with
+// block elements or dots. Very light fills erase everything underneath.
+// Unfilled rectangles are outlined with dots.
+func (g *BrailleGraphics) Rect(x, y, w, h int, style chart.Style) {
+	x, y, w, h = chart.SanitizeRect(x, y, w, h, 1)
+	if style.FillColor == nil {
+		if style.LineWidth > 0 {
+			chart.GenericRect(g, x, y, w, h, style)
+		}
+		return
+	}
+
+	shade := shadeOf(style.FillColor)
+	g.txt.tb.SetColor(style.FillColor, nil)
+	defer g.txt.tb.SetColor(nil, nil)
+	for cy := y / DotsY; cy <= (y+h-1)/DotsY; cy++ {
+		r0, r1 := max(y-cy*DotsY, 0), min(y+h-cy*DotsY, DotsY)
+		for cx := x / DotsX; cx <= (x+w-1)/DotsX; cx++ {
+			c0, c1 := max(x-cx*DotsX, 0), min(x+w-cx*DotsX, DotsX)
+			g.fillCell(cx, cy, c0, c1, r0, r1, shade)
+		}
+	}
+}
+
+// fillCell fills columns [c0,c1) and rows [r0,r1) of cell (cx,cy) with shade
+// (-1 is erase).
+func (g *BrailleGraphics) fillCell(cx, cy, c0, c1, r0, r1 int, shade int) {
+	if c0 >= c1 || r0 >= r1 {
+		return
+	}
+	fullW, fullH := c0 == 0 && c1 == DotsX, r0 == 0 && r1 == DotsY
+	switch {
+	case shade < 0:
+		if fullW && fullH {
+			g.block(cx, cy, ' ')
+		}
+	case fullW && fullH:
+		g.block(cx, cy, shades[shade])
+	case fullW && r1 == DotsY:
+		g.block(cx, cy, lowerBlocks[2*(r1-r0)])
+	case fullW && r0 == 0 && r1 == DotsY/2:
+		g.block(cx, cy, '▀')
+	case fullH && c1-c0 == 1:
+		if c0 == 0 {
+			g.block(cx, cy, '▌')
+		} else {
+			g.block(cx, cy, '▐')
+		}
+	default:
+		for i := c0; i < c1; i++ {
+			for j := r0; j < r1; j++ {
+				g.dot(cx*DotsX+i, cy*DotsY+j)
+			}
+		}
+	}
+}
+
+// shadeOf returns the index into shades used to draw fill color c or -1 if
+// c is that light that it is drawn as blank.
+func shadeOf(c color.Color) int {
+	r, gg, b, a := c.RGBA()
+	// RGBA is alpha-premultiplied: blend onto white background.
+	lum := (0.299*float64(r)+0.587*float64(gg)+0.114*float64(b))/0xffff + 1 - float64(a)/0xffff
+	switch {
+	case lum >= 0.9:
+		return -1
+	case lum >= 0.7:
+		return 0
+	case lum >= 0.45:
+		return 1
+	case lum >= 0.2:
+		return 2
+	}
+	return 3
+}
+
+// String returns the chart: Text and blocks take precedence over dots.
+// Colors are rendered as ANSI escape sequences unless g.Color is NoColor.
+func (g *BrailleGraphics) String() string {
+	tb := *g.txt.tb
+	buf := make([]rune, len(tb.Buf))
+	copy(buf, tb.Buf)
+	for cy := 0; cy < g.h; cy++ {
+		for cx := 0; cx < g.w; cx++ {
+			i := cy*(g.w+1) + cx
+			if d := g.dots[cy*g.w+cx]; d != 0 && buf[i] == ' ' {
+				buf[i] = 0x2800 + d
+			}
+		}
+	}
+	tb.Buf = buf
+	return tb.ANSI(g.Color)
+}
+
+// Symbol puts the plain text symbol at the cell containing (x,y).
+func (g *BrailleGraphics) Symbol(x, y int, style chart.Style) {
+	g.txt.Symbol(x/DotsX, y/DotsY, style)
+}
+
+// scaleRange returns a copy of r which maps to character cells instead of
+// pixels: f is DotsX or DotsY.
+func scaleRange(r chart.Range, f int) chart.Range {
+	d2s, s2d := r.Data2Screen, r.Screen2Data
+	r.Data2Screen = func(x float64) int { return d2s(x) / f }
+	r.Screen2Data = func(x int) float64 { return s2d(f*x + f/2) }
+	return r
+}
+
+func (g *BrailleGraphics) XAxis(xrange chart.Range, y, y1 int, options chart.PlotOptions) {
+	g.txt.XAxis(scaleRange(xrange, DotsX), y/DotsY, y1/DotsY, options)
+}
+
+func (g *BrailleGraphics) YAxis(yrange chart.Range, x, x1 int, options chart.PlotOptions) {
+	g.txt.YAxis(scaleRange(yrange, DotsY), x/DotsX, x1/DotsX, options)
+}
+
+func (g *BrailleGraphics) Scatter(points []chart.EPoint, plotstyle chart.PlotStyle, style chart.Style) {
+	chart.GenericScatter(g, points, plotstyle, style)
+}
+
+func (g *BrailleGraphics) Boxes(boxes []chart.Box, width int, style chart.Style) {
+	chart.GenericBoxes(g, boxes, width, style)
+}
+
+// Key draws a plain text key as TextGraphics does on a cleared background.
+func (g *BrailleGraphics) Key(x, y int, key chart.Key, options chart.PlotOptions) {
+	m := key.Place()
+	if len(m) == 0 {
+		return
+	}
+	x, y = x/DotsX, y/DotsY
+	tw, th, _, _ := key.Layout(g.txt, m, chart.ElementStyle(options, chart.KeyElement).Font)
+	for i := 0; i <= tw; i++ {
+		for j := 0; j < th; j++ {
+			g.block(x+i, y+j, ' ')
+		}
+	}
+	g.txt.Key(x, y, key, options)
+}
+
+func (g *BrailleGraphics) Bars(bars []chart.Barinfo, style chart.Style) {
+	chart.GenericBars(g, bars, style)
+}
+
+func (g *BrailleGraphics) Rings(wedges []chart.Wedgeinfo, x, y, ro, ri int) {
+	chart.GenericRings(g, wedges, x, y, ro, ri, 1)
+}
+
+var _ chart.Graphics = &BrailleGraphics{}
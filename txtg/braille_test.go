@@ -0,0 +1,93 @@
+package txtg
+
+import (
+	"image/color"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/vdobler/chart"
+)
+
+// pixels shows the dots set in g, one line per pixel row.
+func pixels(g *BrailleGraphics) string {
+	var b strings.Builder
+	for y := 0; y < DotsY*g.h; y++ {
+		for x := 0; x < DotsX*g.w; x++ {
+			if g.dots[(y/DotsY)*g.w+x/DotsX]&brailleBit[x%DotsX][y%DotsY] != 0 {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func TestBrailleLine(t *testing.T) {
+	for i, tc := range []struct {
+		x0, y0, x1, y1 int
+		dots, text     string
+	}{
+		{0, 0, 7, 3,
+			"##......\n..##....\n....##..\n......##\n........\n........\n........\n........\n",
+			"⠉⠒⠤⣀\n    \n"},
+		{1, 6, 6, 1,
+			"........\n......#.\n.....#..\n....#...\n...#....\n..#.....\n.#......\n........\n",
+			"  ⡠⠂\n⠠⠊  \n"},
+	} {
+		g := NewBraille(4, 2)
+		g.Line(tc.x0, tc.y0, tc.x1, tc.y1, chart.Style{})
+		if got := pixels(g); got != tc.dots {
+			t.Errorf("%d: got dots\n%s\nexpected\n%s", i, got, tc.dots)
+		}
+		if got := g.String(); got != tc.text {
+			t.Errorf("%d: got %q, expected %q", i, got, tc.text)
+		}
+	}
+}
+
+func TestBrailleRect(t *testing.T) {
+	g := NewBraille(4, 2)
+	g.Rect(1, 1, 6, 5, chart.Style{LineWidth: 1})
+	dots := "........\n.#######\n.#.....#\n.#.....#\n.#.....#\n.#.....#\n.#######\n........\n"
+	if got := pixels(g); got != dots {
+		t.Errorf("outline: got dots\n%s\nexpected\n%s", got, dots)
+	}
+	if got, want := g.String(), "⢰⠒⠒⢲\n⠸⠤⠤⠼\n"; got != want {
+		t.Errorf("outline: got %q, expected %q", got, want)
+	}
+
+	// Full cells are shaded, the partial top row uses lower blocks,
+	// the partial right column a left half block and dots.
+	g = NewBraille(4, 2)
+	g.Rect(0, 1, 5, 7, chart.Style{FillColor: color.Black})
+	if got, want := g.String(), "▆▆⡆ \n██▌ \n"; got != want {
+		t.Errorf("filled: got %q, expected %q", got, want)
+	}
+
+	// Very light fills erase what is underneath.
+	g.Rect(0, 0, 4, 8, chart.Style{FillColor: color.White})
+	if got, want := g.String(), "  ⡆ \n  ▌ \n"; got != want {
+		t.Errorf("erase: got %q, expected %q", got, want)
+	}
+}
+
+func TestBrailleWedge(t *testing.T) {
+	// Angles run clockwise on screen starting at 3 o'clock, like in
+	// chart.GenericWedge.
+	for i, tc := range []struct {
+		phi, psi float64
+		text     string
+	}{
+		{0, math.Pi / 2, "      \n   ⣤⣤⣤\n   ██⠟\n"},
+		{math.Pi, 3 * math.Pi / 2, "⢀⣴⣶⡇  \n⠼⠿⠿⠃  \n      \n"},
+	} {
+		g := NewBraille(6, 3)
+		g.Wedge(6, 6, 6, 0, tc.phi, tc.psi, chart.Style{FillColor: color.Black})
+		if got := g.String(); got != tc.text {
+			t.Errorf("%d: got %q, expected %q", i, got, tc.text)
+		}
+	}
+}
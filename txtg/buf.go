@@ -1,6 +1,7 @@
 package txtg
 
 import (
+	"image/color"
 	"log"
 )
 
@@ -9,8 +10,11 @@ var Edge = [][4]rune{{'+', '+', '+', '+'}, {'.', '.', '\'', '\''}, {'/', '\\', '
 
 // A Text Buffer
 type TextBuf struct {
-	Buf  []rune // the internal buffer.  Point (x,y) is mapped to x + y*(W+1)
-	W, H int    // Width and Height
+	Buf    []rune        // the internal buffer.  Point (x,y) is mapped to x + y*(W+1)
+	Fg, Bg []color.Color // foreground and background color of each cell in Buf, nil is default
+	W, H   int           // Width and Height
+
+	fg, bg color.Color // current colors used by Put
 }
 
 // Set up a new TextBuf with width w and height h.
@@ -18,6 +22,8 @@ func NewTextBuf(w, h int) (tb *TextBuf) {
 	tb = new(TextBuf)
 	tb.W, tb.H = w, h
 	tb.Buf = make([]rune, (w+1)*h)
+	tb.Fg = make([]color.Color, (w+1)*h)
+	tb.Bg = make([]color.Color, (w+1)*h)
 	for i, _ := range tb.Buf {
 		tb.Buf[i] = ' '
 	}
@@ -28,7 +34,13 @@ func NewTextBuf(w, h int) (tb *TextBuf) {
 	return
 }
 
-// Put character c at (x,y)
+// SetColor sets the foreground and background color of all following
+// Put operations. Use nil to reset to the default colors.
+func (tb *TextBuf) SetColor(fg, bg color.Color) {
+	tb.fg, tb.bg = fg, bg
+}
+
+// Put character c at (x,y) in the current colors.
 func (tb *TextBuf) Put(x, y int, c rune) {
 	if x < 0 || y < 0 || x >= tb.W || y >= tb.H || c < ' ' {
 		// debug.Printf("Ooooops Put(): %d, %d, %d='%c' \n", x, y, c, c)
@@ -36,6 +48,7 @@ func (tb *TextBuf) Put(x, y int, c rune) {
 	}
 	i := (tb.W+1)*y + x
 	tb.Buf[i] = c
+	tb.Fg[i], tb.Bg[i] = tb.fg, tb.bg
 }
 
 // Draw rectangle of width w and height h from corner at (x,y).
@@ -130,11 +143,16 @@ func (tb *TextBuf) Text(x, y int, txt string, align int) {
 // Paste buf at (x,y)
 func (tb *TextBuf) Paste(x, y int, buf *TextBuf) {
 	s := buf.W + 1
+	fg, bg := tb.fg, tb.bg
 	for i := 0; i < buf.W; i++ {
 		for j := 0; j < buf.H; j++ {
+			if buf.Fg != nil && buf.Bg != nil {
+				tb.fg, tb.bg = buf.Fg[i+s*j], buf.Bg[i+s*j]
+			}
 			tb.Put(x+i, y+j, buf.Buf[i+s*j])
 		}
 	}
+	tb.fg, tb.bg = fg, bg
 }
 
 func (tb *TextBuf) Line(x0, y0, x1, y1 int, symbol rune) {
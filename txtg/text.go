@@ -3,11 +3,14 @@ package txtg
 import (
 	"fmt"
 	"github.com/vdobler/chart"
+	"image/color"
 	"math"
 )
 
 // TextGraphics
 type TextGraphics struct {
+	Color ColorMode // colors in output of String, use AutoColor to detect terminal capabilities
+
 	tb   *TextBuf // the underlying text buffer
 	w, h int      // width and height
 	xoff int      // the initial radius for pie charts
@@ -40,7 +43,23 @@ func (g *TextGraphics) FontMetrics(font chart.Font) (fw float32, fh int, mono bo
 }
 
 func (g *TextGraphics) TextLen(t string, font chart.Font) int {
-	return len(t)
+	return StrLen(t)
+}
+
+// lineColor returns the color to draw lines in style.
+func lineColor(style chart.Style) color.Color {
+	if style.LineColor != nil {
+		return style.LineColor
+	}
+	return style.FillColor
+}
+
+// symbolColor returns the color to draw symbols in style.
+func symbolColor(style chart.Style) color.Color {
+	if style.SymbolColor != nil {
+		return style.SymbolColor
+	}
+	return lineColor(style)
 }
 
 func (g *TextGraphics) Line(x0, y0, x1, y1 int, style chart.Style) {
@@ -48,7 +67,9 @@ func (g *TextGraphics) Line(x0, y0, x1, y1 int, style chart.Style) {
 	if symbol < ' ' || symbol > '~' {
 		symbol = 'x'
 	}
+	g.tb.SetColor(lineColor(style), nil)
 	g.tb.Line(x0, y0, x1, y1, rune(symbol))
+	g.tb.SetColor(nil, nil)
 }
 func (g *TextGraphics) Path(x, y []int, style chart.Style) {
 	chart.GenericPath(g, x, y, style)
@@ -85,12 +106,16 @@ func (g *TextGraphics) Text(x, y int, t string, align string, rot int, font char
 			a = 4
 		}
 	}
+	g.tb.SetColor(font.Color, nil)
 	g.tb.Text(x, y, t, a)
+	g.tb.SetColor(nil, nil)
 }
 
 func (g *TextGraphics) Rect(x, y, w, h int, style chart.Style) {
 	chart.SanitizeRect(x, y, w, h, 1)
+	defer g.tb.SetColor(nil, nil)
 	// Border
+	g.tb.SetColor(lineColor(style), nil)
 	if style.LineWidth > 0 {
 		for i := 0; i < w; i++ {
 			g.tb.Put(x+i, y, rune(style.Symbol))
@@ -114,6 +139,7 @@ func (g *TextGraphics) Rect(x, y, w, h int, style chart.Style) {
 		} else {
 			s = style.Symbol
 		}
+		g.tb.SetColor(lineColor(style), style.FillColor)
 		for i := 1; i < h-1; i++ {
 			for j := 1; j < w-1; j++ {
 				g.tb.Put(x+j, y+i, rune(s))
@@ -122,12 +148,16 @@ func (g *TextGraphics) Rect(x, y, w, h int, style chart.Style) {
 	}
 }
 
+// String returns the chart as text, with ANSI escape sequences for the
+// colors unless g.Color is NoColor.
 func (g *TextGraphics) String() string {
-	return g.tb.String()
+	return g.tb.ANSI(g.Color)
 }
 
 func (g *TextGraphics) Symbol(x, y int, style chart.Style) {
+	g.tb.SetColor(symbolColor(style), nil)
 	g.tb.Put(x, y, rune(style.Symbol))
+	g.tb.SetColor(nil, nil)
 }
 
 func (g *TextGraphics) XAxis(xrange chart.Range, y, y1 int, options chart.PlotOptions) {
@@ -146,15 +176,16 @@ func (g *TextGraphics) XAxis(xrange chart.Range, y, y1 int, options chart.PlotOp
 		}
 	}
 
+	lp := chart.PlaceXTicLabels(g, xrange, chart.Font{})
 	if xrange.Label != "" {
 		yy := y + 1
 		if !xrange.TicSetting.Hide {
-			yy++
+			yy += lp.Height
 		}
 		g.tb.Text((xa+xe)/2, yy, xrange.Label, 0)
 	}
 
-	for _, tic := range xrange.Tics {
+	for i, tic := range lp.Tics {
 		var x int
 		if !math.IsNaN(tic.Pos) {
 			x = xrange.Data2Screen(tic.Pos)
@@ -162,6 +193,7 @@ func (g *TextGraphics) XAxis(xrange chart.Range, y, y1 int, options chart.PlotOp
 			x = -1
 		}
 		lx := xrange.Data2Screen(tic.LabelPos)
+		ly := y + 1 + lp.Row[i]
 		if xrange.Time {
 			if x != -1 {
 				g.tb.Put(x, y, '|')
@@ -170,10 +202,12 @@ func (g *TextGraphics) XAxis(xrange chart.Range, y, y1 int, options chart.PlotOp
 				}
 				g.tb.Put(x, y+1, '|')
 			}
-			if tic.Align == -1 {
-				g.tb.Text(lx+1, y+1, tic.Label, -1)
+			if lp.Rot != 0 {
+				g.tb.Text(lx, ly, tic.Label, 4)
+			} else if tic.Align == -1 {
+				g.tb.Text(lx+1, ly, tic.Label, -1)
 			} else {
-				g.tb.Text(lx, y+1, tic.Label, 0)
+				g.tb.Text(lx, ly, tic.Label, 0)
 			}
 		} else {
 			if x != -1 {
@@ -182,15 +216,19 @@ func (g *TextGraphics) XAxis(xrange chart.Range, y, y1 int, options chart.PlotOp
 					g.tb.Put(x, y1, '+')
 				}
 			}
-			g.tb.Text(lx, y+1, tic.Label, 0)
+			if lp.Rot != 0 {
+				g.tb.Text(lx, ly, tic.Label, 4)
+			} else {
+				g.tb.Text(lx, ly, tic.Label, 0)
+			}
 		}
 		if xrange.ShowLimits {
 			if xrange.Time {
-				g.tb.Text(xa, y+2, xrange.TMin.Format("2006-01-02 15:04:05"), -1)
-				g.tb.Text(xe, y+2, xrange.TMax.Format("2006-01-02 15:04:05"), 1)
+				g.tb.Text(xa, y+1+lp.Height, xrange.TMin.Format("2006-01-02 15:04:05"), -1)
+				g.tb.Text(xe, y+1+lp.Height, xrange.TMax.Format("2006-01-02 15:04:05"), 1)
 			} else {
-				g.tb.Text(xa, y+2, fmt.Sprintf("%g", xrange.Min), -1)
-				g.tb.Text(xe, y+2, fmt.Sprintf("%g", xrange.Max), 1)
+				g.tb.Text(xa, y+1+lp.Height, fmt.Sprintf("%g", xrange.Min), -1)
+				g.tb.Text(xe, y+1+lp.Height, fmt.Sprintf("%g", xrange.Max), 1)
 			}
 		}
 	}
@@ -241,6 +279,8 @@ func (g *TextGraphics) YAxis(yrange chart.Range, x, x1 int, options chart.PlotOp
 }
 
 func (g *TextGraphics) Scatter(points []chart.EPoint, plotstyle chart.PlotStyle, style chart.Style) {
+	defer g.tb.SetColor(nil, nil)
+
 	// First pass: Error bars
 	for _, p := range points {
 		xl, yl, xh, yh := p.BoundingBox()
@@ -254,6 +294,7 @@ func (g *TextGraphics) Scatter(points []chart.EPoint, plotstyle chart.PlotStyle,
 
 	// Second pass: Line
 	if (plotstyle&chart.PlotStyleLines) != 0 && len(points) > 0 {
+		g.tb.SetColor(lineColor(style), nil)
 		lastx, lasty := int(points[0].X), int(points[0].Y)
 		for i := 1; i < len(points); i++ {
 			x, y := int(points[i].X), int(points[i].Y)
@@ -265,6 +306,7 @@ func (g *TextGraphics) Scatter(points []chart.EPoint, plotstyle chart.PlotStyle,
 
 	// Third pass: symbols
 	if (plotstyle&chart.PlotStylePoints) != 0 && len(points) != 0 {
+		g.tb.SetColor(symbolColor(style), nil)
 		for _, p := range points {
 			g.tb.Put(int(p.X), int(p.Y), rune(style.Symbol))
 		}
@@ -280,40 +322,92 @@ func (g *TextGraphics) Boxes(boxes []chart.Box, width int, style chart.Style) {
 	if style.Symbol == 0 {
 		style.Symbol = '*'
 	}
+	g.tb.SetColor(lineColor(style), nil)
+	defer g.tb.SetColor(nil, nil)
 
 	for _, box := range boxes {
+		// Box coordinates: v across the box, u along the values.
+		horizontal := box.Horizontal
+		put := func(v, u int, r rune) {
+			if horizontal {
+				g.tb.Put(u, v, r)
+			} else {
+				g.tb.Put(v, u, r)
+			}
+		}
+		along, across := '|', '-'
+		if horizontal {
+			along, across = '-', '|'
+		}
+
 		x := int(box.X)
 		q1, q3 := int(box.Q1), int(box.Q3)
-		g.tb.Rect(x-hbw, q1, 2*hbw, q3-q1, 0, ' ')
+		if horizontal {
+			g.tb.Rect(q1, x-hbw, q3-q1, 2*hbw, 0, ' ')
+		} else {
+			g.tb.Rect(x-hbw, q1, 2*hbw, q3-q1, 0, ' ')
+		}
 		if !math.IsNaN(box.Med) {
 			med := int(box.Med)
-			g.tb.Put(x-hbw, med, '+')
+			put(x-hbw, med, '+')
 			for i := 0; i < hbw; i++ {
-				g.tb.Put(x-i, med, '-')
-				g.tb.Put(x+i, med, '-')
+				put(x-i, med, across)
+				put(x+i, med, across)
+			}
+			put(x+hbw, med, '+')
+
+			if box.NotchLow != box.NotchHigh {
+				// The sides of the box point inwards at the median.
+				if horizontal {
+					put(x-hbw, med, 'v')
+					put(x+hbw, med, '^')
+				} else {
+					put(x-hbw, med, '>')
+					put(x+hbw, med, '<')
+				}
+				lo, hi := q1, q3
+				if lo > hi {
+					lo, hi = hi, lo
+				}
+				n1, n2 := int(box.NotchLow), int(box.NotchHigh)
+				if n1 > n2 {
+					n1, n2 = n2, n1
+				}
+				if n1 > lo && n1 < med {
+					put(x-hbw, n1, '\\')
+					put(x+hbw, n1, '/')
+				}
+				if n2 < hi && n2 > med {
+					put(x-hbw, n2, '/')
+					put(x+hbw, n2, '\\')
+				}
 			}
-			g.tb.Put(x+hbw, med, '+')
 		}
 
 		if !math.IsNaN(box.Avg) && style.Symbol != 0 {
-			g.tb.Put(x, int(box.Avg), rune(style.Symbol))
+			put(x, int(box.Avg), rune(style.Symbol))
 		}
 
-		if !math.IsNaN(box.High) {
-			for y := int(box.High); y < q3; y++ {
-				g.tb.Put(x, y, '|')
+		// Whiskers from their end towards the edge of the box.
+		whisker := func(end, edge int) {
+			step := 1
+			if end > edge {
+				step = -1
+			}
+			for u := end; u != edge; u += step {
+				put(x, u, along)
 			}
 		}
+		if !math.IsNaN(box.High) {
+			whisker(int(box.High), q3)
+		}
 
 		if !math.IsNaN(box.Low) {
-			for y := int(box.Low); y > q1; y-- {
-				g.tb.Put(x, y, '|')
-			}
+			whisker(int(box.Low), q1)
 		}
 
 		for _, ol := range box.Outliers {
-			y := int(ol)
-			g.tb.Put(x, y, rune(style.Symbol))
+			put(x, int(ol), rune(style.Symbol))
 		}
 	}
 }
@@ -356,7 +450,9 @@ func (g *TextGraphics) Key(x, y int, key chart.Key, options chart.PlotOptions) {
 					g.Symbol(x+int(chart.KeySymbolWidth/2), yy, e.Style)
 				}
 				if (plotStyle & chart.PlotStyleBox) != 0 {
+					g.tb.SetColor(lineColor(e.Style), e.Style.FillColor)
 					g.tb.Put(x+int(chart.KeySymbolWidth/2), yy, rune(e.Style.Symbol))
+					g.tb.SetColor(nil, nil)
 				}
 				g.tb.Text(x+int((chart.KeySymbolWidth+chart.KeySymbolSep)), yy, e.Text, -1)
 			}
@@ -374,6 +470,12 @@ func (g *TextGraphics) Bars(bars []chart.Barinfo, style chart.Style) {
 
 var CircleStretchFactor float64 = 1.85
 
+// CircleStretch reports CircleStretchFactor to the charts. Rings shifts the
+// pie to the right by the additional width of its outer ring.
+func (g *TextGraphics) CircleStretch() float64 {
+	return CircleStretchFactor
+}
+
 func (g *TextGraphics) Rings(wedges []chart.Wedgeinfo, x, y, ro, ri int) {
 	if g.xoff == -1 {
 		g.xoff = int(float64(ro) * (CircleStretchFactor - 1))
@@ -382,7 +484,7 @@ func (g *TextGraphics) Rings(wedges []chart.Wedgeinfo, x, y, ro, ri int) {
 	for i := range wedges {
 		wedges[i].Style.LineWidth = 1
 	}
-	chart.GenericRings(g, wedges, x+g.xoff, y, ro, ri, 1.8)
+	chart.GenericRings(g, wedges, x+g.xoff, y, ro, ri, CircleStretchFactor)
 }
 
 var _ chart.Graphics = &TextGraphics{}
@@ -0,0 +1,222 @@
+package chart
+
+import (
+	"image/color"
+	"math"
+)
+
+// WaterfallKind distinguishes the bars of a waterfall chart.
+type WaterfallKind int
+
+const (
+	WaterfallDelta    WaterfallKind = iota // floating bar: change of running total by Val
+	WaterfallTotal                         // bar from zero to the running total (computed automatically)
+	WaterfallAbsolute                      // bar from zero to Val which becomes the new running total
+)
+
+// WaterfallStep is one bar in a waterfall chart.
+type WaterfallStep struct {
+	Cat  string
+	Val  float64
+	Kind WaterfallKind
+}
+
+// WaterfallChart draws waterfall (bridge) charts: Starting from an absolute
+// value the running total is changed by positive and negative contributions
+// drawn as floating bars connected by lines. Subtotals and totals are drawn
+// as bars starting at zero.
+// The steps are placed on a categorical x axis (the categories are added to
+// XRange.Category). The styles must be set before adding steps.
+type WaterfallChart struct {
+	XRange, YRange Range
+	Title          string               // Title of the chart
+	Key            Key                  // Key/Legend
+	ShowVal        int                  // Display values: 0: don't show; 1: above bar, 2: centerd in bar; 3: at top of bar
+	FmtVal         func(float64) string // format shown values, nil for default
+	BarWidthFac    float64              // width of bars relative to distance of categories, 0 is 0.6
+	Increase       Style                // style of increasing bars, empty uses green
+	Decrease       Style                // style of decreasing bars, empty uses red
+	TotalStyle     Style                // style of total and absolute bars, empty uses blue
+	Connector      Style                // style of lines connecting bars, empty uses gray dashed line
+	HideConnectors bool                 // do not draw the connecting lines
+	Options        PlotOptions          // visual apperance, nil to use DefaultOptions
+	Annotations    Annotations          // text, arrows, reference lines and spans
+	Steps          []WaterfallStep
+}
+
+// AddStart adds a bar of absolute value val (the start value) labeled cat.
+func (c *WaterfallChart) AddStart(cat string, val float64) {
+	c.add(WaterfallStep{Cat: cat, Val: val, Kind: WaterfallAbsolute})
+}
+
+// AddStep adds a bar labeled cat changing the running total by delta.
+func (c *WaterfallChart) AddStep(cat string, delta float64) {
+	c.add(WaterfallStep{Cat: cat, Val: delta, Kind: WaterfallDelta})
+}
+
+// AddTotal adds a (sub)total bar labeled cat showing the current running total.
+func (c *WaterfallChart) AddTotal(cat string) {
+	c.add(WaterfallStep{Cat: cat, Kind: WaterfallTotal})
+}
+
+func (c *WaterfallChart) add(step WaterfallStep) {
+	c.Steps = append(c.Steps, step)
+	c.XRange.Category = append(c.XRange.Category, step.Cat)
+
+	// Key entries for the used bar types.
+	text, style := "Total", c.style(0, WaterfallTotal)
+	if step.Kind == WaterfallDelta {
+		text, style = "Increase", c.style(1, WaterfallDelta)
+		if step.Val < 0 {
+			text, style = "Decrease", c.style(-1, WaterfallDelta)
+		}
+	}
+	for _, e := range c.Key.Entries {
+		if e.Text == text && e.PlotStyle == PlotStyleBox {
+			return
+		}
+	}
+	c.Key.Entries = append(c.Key.Entries, KeyEntry{Style: style, Text: text, PlotStyle: PlotStyleBox})
+}
+
+// style returns the style of bars of kind with a change of sign delta.
+func (c *WaterfallChart) style(delta float64, kind WaterfallKind) Style {
+	switch {
+	case kind != WaterfallDelta:
+		if c.TotalStyle.empty() {
+			return Style{Symbol: '#', LineColor: color.NRGBA{0x30, 0x50, 0xa0, 0xff}, LineWidth: 1, LineStyle: SolidLine,
+				FillColor: color.NRGBA{0x60, 0x80, 0xd0, 0xff}}
+		}
+		return c.TotalStyle
+	case delta < 0:
+		if c.Decrease.empty() {
+			return Style{Symbol: '-', LineColor: color.NRGBA{0xb0, 0x00, 0x00, 0xff}, LineWidth: 1, LineStyle: SolidLine,
+				FillColor: color.NRGBA{0xf0, 0x50, 0x50, 0xff}}
+		}
+		return c.Decrease
+	}
+	if c.Increase.empty() {
+		return Style{Symbol: '+', LineColor: color.NRGBA{0x00, 0x90, 0x00, 0xff}, LineWidth: 1, LineStyle: SolidLine,
+			FillColor: color.NRGBA{0x50, 0xd0, 0x50, 0xff}}
+	}
+	return c.Increase
+}
+
+// levels returns the start and end value of each bar.
+func (c *WaterfallChart) levels() (from, to []float64) {
+	from, to = make([]float64, len(c.Steps)), make([]float64, len(c.Steps))
+	total := 0.0
+	for i, s := range c.Steps {
+		switch s.Kind {
+		case WaterfallDelta:
+			from[i], to[i] = total, total+s.Val
+			total += s.Val
+		case WaterfallTotal:
+			from[i], to[i] = 0, total
+		case WaterfallAbsolute:
+			from[i], to[i] = 0, s.Val
+			total = s.Val
+		}
+	}
+	return from, to
+}
+
+// Reset chart to state before plotting.
+func (c *WaterfallChart) Reset() {
+	c.XRange.Reset()
+	c.YRange.Reset()
+}
+
+// Plot renders the chart to the graphics output g.
+func (c *WaterfallChart) Plot(g Graphics) {
+	from, to := c.levels()
+	c.XRange.init()
+	c.YRange.init()
+	for i := range c.Steps {
+		c.XRange.autoscale(float64(i))
+		c.YRange.autoscale(from[i])
+		c.YRange.autoscale(to[i])
+	}
+	// Bars start at zero: Do not expand the y axis below resp. above zero.
+	// The range modes of the user are restored after setting up the axis.
+	minMode, maxMode := c.YRange.MinMode, c.YRange.MaxMode
+	if c.YRange.DataMin >= 0 && !minMode.Fixed {
+		c.YRange.DataMin = 0
+		c.YRange.MinMode.Fixed, c.YRange.MinMode.Value = true, 0
+	}
+	if c.YRange.DataMax <= 0 && !maxMode.Fixed {
+		c.YRange.DataMax = 0
+		c.YRange.MaxMode.Fixed, c.YRange.MaxMode.Value = true, 0
+	}
+
+	// layout
+	layout := layout(g, c.Title, &c.XRange, &c.YRange, &c.Key, c.Options)
+	width, height := layout.Width, layout.Height
+	topm, leftm := layout.Top, layout.Left
+	numxtics, numytics := layout.NumXtics, layout.NumYtics
+	font := elementStyle(c.Options, MajorAxisElement).Font
+	fw, fh, _ := g.FontMetrics(font)
+
+	// Outside bound ranges for bar plots are nicer
+	leftm += int(2 * fw)
+	width -= int(2 * fw)
+	height -= fh
+
+	c.XRange.Setup(numxtics, numxtics+3, width, leftm, false)
+	c.YRange.Setup(numytics, numytics+2, height, topm, true)
+	c.YRange.MinMode, c.YRange.MaxMode = minMode, maxMode
+
+	// Start of drawing
+	g.Begin()
+	if c.Title != "" {
+		drawTitle(g, c.Title, elementStyle(c.Options, TitleElement))
+	}
+
+	drawAnnotations(g, c.Annotations, BackgroundLayer, c.XRange, c.YRange, c.Options)
+	g.XAxis(c.XRange, topm+height+fh, topm, c.Options)
+	g.YAxis(c.YRange, leftm-int(2*fw), leftm+width, c.Options)
+	drawAnnotations(g, c.Annotations, BelowDataLayer, c.XRange, c.YRange, c.Options)
+
+	xf := c.XRange.Data2Screen
+	yf := c.YRange.Data2Screen
+	fac := c.BarWidthFac
+	if fac <= 0 {
+		fac = 0.6
+	}
+	sbw := imax(1, int(fac*float64(xf(1)-xf(0))))
+
+	// Connectors first so that bars are drawn on top of them.
+	if !c.HideConnectors {
+		style := c.Connector
+		if style.empty() {
+			style = Style{Symbol: '.', LineColor: color.NRGBA{0x80, 0x80, 0x80, 0xff}, LineWidth: 1, LineStyle: DashedLine}
+		}
+		for i := 0; i+1 < len(c.Steps); i++ {
+			y := yf(to[i])
+			if c.Steps[i+1].Kind == WaterfallAbsolute {
+				continue
+			}
+			g.Line(xf(float64(i))+sbw/2, y, xf(float64(i+1))-sbw/2, y, style)
+		}
+	}
+
+	for i, s := range c.Steps {
+		sy := yf(math.Max(from[i], to[i]))
+		sh := imax(1, yf(math.Min(from[i], to[i]))-sy)
+		bar := Barinfo{x: xf(float64(i)) - sbw/2, y: sy, w: sbw, h: sh}
+		val := to[i] - from[i]
+		if s.Kind != WaterfallDelta {
+			val = to[i]
+		}
+		barLabel(&bar, val, val < 0, c.ShowVal, c.FmtVal)
+		g.Bars([]Barinfo{bar}, c.style(val, s.Kind))
+	}
+
+	drawAnnotations(g, c.Annotations, AboveDataLayer, c.XRange, c.YRange, c.Options)
+
+	if !c.Key.Hide {
+		g.Key(layout.KeyX, layout.KeyY, c.Key, c.Options)
+	}
+
+	g.End()
+}
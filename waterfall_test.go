@@ -0,0 +1,31 @@
+package chart
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWaterfallLevels(t *testing.T) {
+	c := WaterfallChart{}
+	c.AddStart("Start", 10)
+	c.AddStep("Up", 5)
+	c.AddStep("Down", -8)
+	c.AddTotal("Sub")
+	c.AddStep("Down", -9)
+	c.AddTotal("End")
+
+	from, to := c.levels()
+	got := ""
+	for i := range from {
+		got += fmt.Sprintf("%g:%g ", from[i], to[i])
+	}
+	if expected := "0:10 10:15 15:7 0:7 7:-2 0:-2 "; got != expected {
+		t.Errorf("Got %q, expected %q", got, expected)
+	}
+	if len(c.XRange.Category) != 6 {
+		t.Errorf("Got %d categories, expected 6", len(c.XRange.Category))
+	}
+	if len(c.Key.Entries) != 3 {
+		t.Errorf("Got %d key entries, expected 3", len(c.Key.Entries))
+	}
+}